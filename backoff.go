@@ -0,0 +1,81 @@
+package freecap
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// defaultMaxRetryDelay caps the default exponential backoff
+const defaultMaxRetryDelay = 30 * time.Second
+
+// maxBackoffShift is the largest doubling exponent that fits in a Duration
+const maxBackoffShift = 62
+
+// BackoffStrategy computes the delay before a retry. Attempt is zero-based:
+// 0 is the delay after the first failed attempt.
+type BackoffStrategy interface {
+	NextDelay(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same delay before every retry
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) NextDelay(attempt int) time.Duration {
+	if b.Delay < 0 {
+		return 0
+	}
+	return b.Delay
+}
+
+// ExponentialBackoff doubles BaseDelay on every retry, capped at MaxDelay
+// (0 = capped only by the largest Duration). It never overflows or returns
+// a negative delay, however high the attempt.
+type ExponentialBackoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func (b ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	if b.BaseDelay <= 0 {
+		return 0
+	}
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	limit := b.MaxDelay
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+
+	if attempt > maxBackoffShift || b.BaseDelay > limit>>uint(attempt) {
+		return limit
+	}
+
+	delay := b.BaseDelay << uint(attempt)
+	if delay > limit {
+		delay = limit
+	}
+	return delay
+}
+
+// JitteredBackoff picks a random delay between zero and the exponential delay
+// ("full jitter"), spreading out retries from concurrent callers
+type JitteredBackoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func (b JitteredBackoff) NextDelay(attempt int) time.Duration {
+	ceiling := ExponentialBackoff{BaseDelay: b.BaseDelay, MaxDelay: b.MaxDelay}.NextDelay(attempt)
+	if ceiling <= 0 {
+		return 0
+	}
+	if ceiling == math.MaxInt64 {
+		return time.Duration(rand.Int63n(int64(ceiling)))
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
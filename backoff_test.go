@@ -0,0 +1,90 @@
+package freecap
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	b := ConstantBackoff{Delay: 200 * time.Millisecond}
+	for attempt := 0; attempt < 5; attempt++ {
+		if got := b.NextDelay(attempt); got != 200*time.Millisecond {
+			t.Errorf("NextDelay(%d) = %v, want 200ms", attempt, got)
+		}
+	}
+	if got := (ConstantBackoff{Delay: -time.Second}).NextDelay(0); got != 0 {
+		t.Errorf("negative delay NextDelay = %v, want 0", got)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for attempt, delay := range want {
+		if got := b.NextDelay(attempt); got != delay {
+			t.Errorf("NextDelay(%d) = %v, want %v", attempt, got, delay)
+		}
+	}
+	if got := b.NextDelay(-3); got != 100*time.Millisecond {
+		t.Errorf("NextDelay(-3) = %v, want the base delay", got)
+	}
+	if got := (ExponentialBackoff{MaxDelay: time.Second}).NextDelay(2); got != 0 {
+		t.Errorf("zero base NextDelay = %v, want 0", got)
+	}
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	b := JitteredBackoff{BaseDelay: 100 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+	ceilings := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		500 * time.Millisecond,
+		500 * time.Millisecond,
+	}
+	for attempt, ceiling := range ceilings {
+		for i := 0; i < 100; i++ {
+			if got := b.NextDelay(attempt); got < 0 || got > ceiling {
+				t.Fatalf("NextDelay(%d) = %v, want within [0, %v]", attempt, got, ceiling)
+			}
+		}
+	}
+}
+
+func TestBackoffLargeAttemptsStayCapped(t *testing.T) {
+	strategies := []struct {
+		name    string
+		backoff BackoffStrategy
+		limit   time.Duration
+	}{
+		{"exponential capped", ExponentialBackoff{BaseDelay: time.Second, MaxDelay: time.Minute}, time.Minute},
+		{"exponential uncapped", ExponentialBackoff{BaseDelay: time.Second}, time.Duration(math.MaxInt64)},
+		{"jittered capped", JitteredBackoff{BaseDelay: time.Second, MaxDelay: time.Minute}, time.Minute},
+		{"jittered uncapped", JitteredBackoff{BaseDelay: time.Second}, time.Duration(math.MaxInt64)},
+	}
+	for _, tt := range strategies {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, attempt := range []int{30, 33, 62, 63, 64, 100, 1000, math.MaxInt32} {
+				got := tt.backoff.NextDelay(attempt)
+				if got < 0 || got > tt.limit {
+					t.Errorf("NextDelay(%d) = %v, want within [0, %v]", attempt, got, tt.limit)
+				}
+			}
+		})
+	}
+
+	exp := ExponentialBackoff{BaseDelay: time.Second, MaxDelay: time.Minute}
+	for _, attempt := range []int{63, 64, 1000} {
+		if got := exp.NextDelay(attempt); got != time.Minute {
+			t.Errorf("NextDelay(%d) = %v, want the 1m cap", attempt, got)
+		}
+	}
+}
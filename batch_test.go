@@ -0,0 +1,159 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// batchTasks returns hCaptcha tasks whose sitekeys become their task IDs
+func batchTasks(sitekeys ...string) []*CaptchaTask {
+	tasks := make([]*CaptchaTask, len(sitekeys))
+	for i, sitekey := range sitekeys {
+		tasks[i] = hcaptchaTask()
+		tasks[i].Sitekey = sitekey
+	}
+	return tasks
+}
+
+// handleBatchCreate answers /CreateTask with task-<sitekey>
+func handleBatchCreate(api *fakeAPI, onCreate func()) {
+	api.handle("/CreateTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if onCreate != nil {
+			onCreate()
+		}
+		sitekey := body["payload"].(map[string]interface{})["websiteKey"]
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "taskId": fmt.Sprintf("task-%v", sitekey)})
+	})
+}
+
+func TestSolveBatchKeepsInputOrder(t *testing.T) {
+	api := newFakeAPI(t)
+	handleBatchCreate(api, nil)
+	// Earlier tasks take longer, so they finish last
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		taskID := body["taskId"].(string)
+		var n int
+		fmt.Sscanf(strings.TrimPrefix(taskID, "task-key"), "%d", &n)
+		time.Sleep(time.Duration(4-n) * 10 * time.Millisecond)
+		writeTestJSON(w, 200, taskSolved("solution-"+taskID))
+	})
+	client := newTestClient(t, api.URL, nil)
+
+	results, err := client.SolveBatch(context.Background(), batchTasks("key0", "key1", "key2", "key3"), HCaptcha, 4)
+	if err != nil {
+		t.Fatalf("SolveBatch: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("results = %d, want 4", len(results))
+	}
+	for i, result := range results {
+		want := fmt.Sprintf("solution-task-key%d", i)
+		if result.Index != i || result.Solution != want || result.Err != nil {
+			t.Errorf("results[%d] = %+v, want index %d and %q", i, result, i, want)
+		}
+	}
+}
+
+func TestSolveBatchConcurrencyLimit(t *testing.T) {
+	const concurrency, solves = 3, 10
+
+	api := newFakeAPI(t)
+	var active, peak atomic.Int32
+	handleBatchCreate(api, func() {
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+	})
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		time.Sleep(15 * time.Millisecond)
+		active.Add(-1)
+		writeTestJSON(w, 200, taskSolved("P1_token"))
+	})
+	client := newTestClient(t, api.URL, nil)
+
+	sitekeys := make([]string, solves)
+	for i := range sitekeys {
+		sitekeys[i] = fmt.Sprintf("key%d", i)
+	}
+	results, err := client.SolveBatch(context.Background(), batchTasks(sitekeys...), HCaptcha, concurrency)
+	if err != nil {
+		t.Fatalf("SolveBatch: %v", err)
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("results[%d].Err = %v", i, result.Err)
+		}
+	}
+	if p := peak.Load(); p > concurrency {
+		t.Errorf("peak in-flight solves = %d, want at most %d", p, concurrency)
+	}
+	if p := peak.Load(); p < 2 {
+		t.Errorf("peak in-flight solves = %d, want solves to overlap", p)
+	}
+}
+
+func TestSolveBatchCancelKeepsFinished(t *testing.T) {
+	api := newFakeAPI(t)
+	handleBatchCreate(api, nil)
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if strings.HasPrefix(body["taskId"].(string), "task-fast") {
+			writeTestJSON(w, 200, taskSolved("P1_token"))
+			return
+		}
+		writeTestJSON(w, 200, taskStatus(Processing))
+	})
+	api.handle("/DeleteTask", respond(200, map[string]interface{}{"status": true}))
+	client := newTestClient(t, api.URL, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// Both workers are polling slow tasks once the fast ones are done
+		for len(api.requestsTo("/GetTask")) < 4 || api.calls("/CreateTask") < 4 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	results, err := client.SolveBatch(ctx, batchTasks("fast0", "fast1", "slow2", "slow3", "slow4"), HCaptcha, 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SolveBatch error = %v, want context.Canceled", err)
+	}
+	for i := 0; i < 2; i++ {
+		if results[i].Solution != "P1_token" || results[i].Err != nil {
+			t.Errorf("results[%d] = %+v, want the solve finished before cancelling", i, results[i])
+		}
+	}
+	for i := 2; i < len(results); i++ {
+		if results[i].Solution != "" || !errors.Is(results[i].Err, context.Canceled) {
+			t.Errorf("results[%d] = %+v, want context.Canceled", i, results[i])
+		}
+	}
+	if results[4].Err != context.Canceled {
+		t.Errorf("results[4].Err = %v, want ctx.Err() for a task never started", results[4].Err)
+	}
+	if n := api.calls("/CreateTask"); n != 4 {
+		t.Errorf("/CreateTask calls = %d, want 4 for the tasks started before cancelling", n)
+	}
+}
+
+func TestSolveBatchValidation(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, nil)
+	if _, err := client.SolveBatch(context.Background(), batchTasks("key0"), HCaptcha, 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("SolveBatch(concurrency 0) = %v, want validation error", err)
+	}
+	results, err := client.SolveBatch(context.Background(), []*CaptchaTask{nil}, HCaptcha, 1)
+	if err != nil || len(results) != 1 || !errors.Is(results[0].Err, ErrValidation) {
+		t.Errorf("SolveBatch(nil task) = %+v, %v; want a validation error in the result", results, err)
+	}
+}
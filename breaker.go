@@ -0,0 +1,125 @@
+package freecap
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultBreakerCooldown is used when BreakerCooldown is not set
+const defaultBreakerCooldown = 30 * time.Second
+
+// BreakerState represents the state of the client's circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets requests through normally
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails requests fast until the cooldown elapses
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe request through
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker opens after threshold consecutive failures and half-opens
+// after cooldown to probe whether the backend has recovered
+type circuitBreaker struct {
+	mu        sync.Mutex
+	clock     Clock
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     BreakerState
+	openedAt  time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(clock Clock, threshold int, cooldown time.Duration) *circuitBreaker {
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &circuitBreaker{
+		clock:     clock,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a request may be attempted
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a request that reached a healthy backend
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.state = BreakerClosed
+	b.probing = false
+}
+
+// failure records a network error or server error
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.clock.Now()
+	}
+}
+
+// abort releases a half-open probe that ended without a verdict
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// currentState returns the breaker state, reporting an expired open breaker
+// as half-open
+func (b *circuitBreaker) currentState() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.clock.Now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
@@ -0,0 +1,79 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerLifecycle(t *testing.T) {
+	api := newFakeAPI(t)
+	var healthy atomic.Bool
+	api.handle("/GetBalance", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if !healthy.Load() {
+			writeTestJSON(w, 500, map[string]interface{}{"status": false, "error": "down"})
+			return
+		}
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "balance": 1.0})
+	})
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.MaxRetries = 0
+		config.BreakerThreshold = 2
+		config.BreakerCooldown = 100 * time.Millisecond
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetBalance(ctx); !errors.Is(err, ErrServerError) {
+			t.Fatalf("failure %d = %v, want ErrServerError", i+1, err)
+		}
+	}
+	if state := client.BreakerState(); state != BreakerOpen {
+		t.Fatalf("state after %d failures = %v, want open", 2, state)
+	}
+
+	// Open: requests fail fast without reaching the server
+	if _, err := client.GetBalance(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetBalance while open = %v, want ErrCircuitOpen", err)
+	}
+	if n := api.calls("/GetBalance"); n != 2 {
+		t.Errorf("server saw %d requests, want 2; the open breaker let one through", n)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if state := client.BreakerState(); state != BreakerHalfOpen {
+		t.Fatalf("state after the cooldown = %v, want half-open", state)
+	}
+
+	// Half-open: a successful probe closes the breaker
+	healthy.Store(true)
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("probe request: %v", err)
+	}
+	if state := client.BreakerState(); state != BreakerClosed {
+		t.Errorf("state after a successful probe = %v, want closed", state)
+	}
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Errorf("GetBalance once closed: %v", err)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	b := newCircuitBreaker(realClock{}, 1, 50*time.Millisecond)
+	b.failure()
+	time.Sleep(60 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("probe not allowed after the cooldown")
+	}
+	if b.allow() {
+		t.Error("a second request allowed while the probe is in flight")
+	}
+	b.failure()
+	if state := b.currentState(); state != BreakerOpen {
+		t.Errorf("state after a failed probe = %v, want open", state)
+	}
+}
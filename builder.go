@@ -0,0 +1,255 @@
+package freecap
+
+// Task builders construct a CaptchaTask for one captcha type, exposing only
+// the fields that type uses. Build validates the task:
+//
+//	task, err := freecap.NewHCaptchaTask(sitekey, siteurl).
+//		WithGroqKey(groqKey).
+//		WithProxy(proxy).
+//		Build()
+
+// HCaptchaTaskBuilder builds hCaptcha tasks
+type HCaptchaTaskBuilder struct{ task CaptchaTask }
+
+// NewHCaptchaTask starts an hCaptcha task for the given site
+func NewHCaptchaTask(sitekey, siteurl string) *HCaptchaTaskBuilder {
+	return &HCaptchaTaskBuilder{task: CaptchaTask{Sitekey: sitekey, Siteurl: siteurl}}
+}
+
+// WithRqData sets the rqdata value
+func (b *HCaptchaTaskBuilder) WithRqData(rqData string) *HCaptchaTaskBuilder {
+	b.task.RqData = rqData
+	return b
+}
+
+// WithGroqKey sets the Groq API key
+func (b *HCaptchaTaskBuilder) WithGroqKey(groqAPIKey string) *HCaptchaTaskBuilder {
+	b.task.GroqAPIKey = groqAPIKey
+	return b
+}
+
+// ForDiscord marks the task as a Discord hCaptcha, which requires rqdata and a Groq key
+func (b *HCaptchaTaskBuilder) ForDiscord() *HCaptchaTaskBuilder {
+	b.task.Discord = true
+	return b
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *HCaptchaTaskBuilder) WithProxy(proxy string) *HCaptchaTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *HCaptchaTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, HCaptcha)
+}
+
+// CaptchaFoxTaskBuilder builds CaptchaFox tasks
+type CaptchaFoxTaskBuilder struct{ task CaptchaTask }
+
+// NewCaptchaFoxTask starts a CaptchaFox task for the given site
+func NewCaptchaFoxTask(sitekey, siteurl string) *CaptchaFoxTaskBuilder {
+	return &CaptchaFoxTaskBuilder{task: CaptchaTask{Sitekey: sitekey, Siteurl: siteurl}}
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *CaptchaFoxTaskBuilder) WithProxy(proxy string) *CaptchaFoxTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *CaptchaFoxTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, CaptchaFox)
+}
+
+// GeetestTaskBuilder builds Geetest tasks
+type GeetestTaskBuilder struct{ task CaptchaTask }
+
+// NewGeetestTask starts a Geetest task for the given challenge, defaulting
+// to the slide risk type
+func NewGeetestTask(challenge string) *GeetestTaskBuilder {
+	return &GeetestTaskBuilder{task: CaptchaTask{Challenge: challenge, RiskType: Slide}}
+}
+
+// WithRiskType sets the Geetest risk type
+func (b *GeetestTaskBuilder) WithRiskType(riskType RiskType) *GeetestTaskBuilder {
+	b.task.RiskType = riskType
+	return b
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *GeetestTaskBuilder) WithProxy(proxy string) *GeetestTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *GeetestTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, Geetest)
+}
+
+// DiscordIDTaskBuilder builds Discord ID tasks
+type DiscordIDTaskBuilder struct{ task CaptchaTask }
+
+// NewDiscordIDTask starts a Discord ID task for the given site
+func NewDiscordIDTask(sitekey, siteurl string) *DiscordIDTaskBuilder {
+	return &DiscordIDTaskBuilder{task: CaptchaTask{Sitekey: sitekey, Siteurl: siteurl}}
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *DiscordIDTaskBuilder) WithProxy(proxy string) *DiscordIDTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *DiscordIDTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, DiscordID)
+}
+
+// FunCaptchaTaskBuilder builds FunCaptcha tasks
+type FunCaptchaTaskBuilder struct{ task CaptchaTask }
+
+// NewFunCaptchaTask starts a FunCaptcha task for the given preset, with the
+// same chrome_version and blob defaults as NewCaptchaTask
+func NewFunCaptchaTask(preset FunCaptchaPreset) *FunCaptchaTaskBuilder {
+	defaults := NewCaptchaTask()
+	return &FunCaptchaTaskBuilder{task: CaptchaTask{
+		Preset:        preset,
+		ChromeVersion: defaults.ChromeVersion,
+		Blob:          defaults.Blob,
+	}}
+}
+
+// WithChromeVersion sets the chrome_version value
+func (b *FunCaptchaTaskBuilder) WithChromeVersion(chromeVersion string) *FunCaptchaTaskBuilder {
+	b.task.ChromeVersion = chromeVersion
+	return b
+}
+
+// WithBlob sets the blob value
+func (b *FunCaptchaTaskBuilder) WithBlob(blob string) *FunCaptchaTaskBuilder {
+	b.task.Blob = blob
+	return b
+}
+
+// WithLiteralBlob sends the blob verbatim, even when empty or "undefined"
+func (b *FunCaptchaTaskBuilder) WithLiteralBlob() *FunCaptchaTaskBuilder {
+	b.task.SendLiteralBlob = true
+	return b
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *FunCaptchaTaskBuilder) WithProxy(proxy string) *FunCaptchaTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *FunCaptchaTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, FunCaptcha)
+}
+
+// TurnstileTaskBuilder builds Turnstile tasks
+type TurnstileTaskBuilder struct{ task CaptchaTask }
+
+// NewTurnstileTask starts a Turnstile task for the given site
+func NewTurnstileTask(sitekey, siteurl string) *TurnstileTaskBuilder {
+	return &TurnstileTaskBuilder{task: CaptchaTask{Sitekey: sitekey, Siteurl: siteurl}}
+}
+
+// WithAction sets the widget action
+func (b *TurnstileTaskBuilder) WithAction(action string) *TurnstileTaskBuilder {
+	b.task.Action = action
+	return b
+}
+
+// WithCData sets the widget cdata
+func (b *TurnstileTaskBuilder) WithCData(cdata string) *TurnstileTaskBuilder {
+	b.task.CData = cdata
+	return b
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *TurnstileTaskBuilder) WithProxy(proxy string) *TurnstileTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *TurnstileTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, Turnstile)
+}
+
+// RecaptchaV2TaskBuilder builds reCAPTCHA v2 tasks
+type RecaptchaV2TaskBuilder struct{ task CaptchaTask }
+
+// NewRecaptchaV2Task starts a reCAPTCHA v2 task for the given site
+func NewRecaptchaV2Task(sitekey, siteurl string) *RecaptchaV2TaskBuilder {
+	return &RecaptchaV2TaskBuilder{task: CaptchaTask{Sitekey: sitekey, Siteurl: siteurl}}
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *RecaptchaV2TaskBuilder) WithProxy(proxy string) *RecaptchaV2TaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *RecaptchaV2TaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, RecaptchaV2)
+}
+
+// RecaptchaV3TaskBuilder builds reCAPTCHA v3 tasks
+type RecaptchaV3TaskBuilder struct{ task CaptchaTask }
+
+// NewRecaptchaV3Task starts a reCAPTCHA v3 task for the given site and action
+func NewRecaptchaV3Task(sitekey, siteurl, action string) *RecaptchaV3TaskBuilder {
+	return &RecaptchaV3TaskBuilder{task: CaptchaTask{Sitekey: sitekey, Siteurl: siteurl, Action: action}}
+}
+
+// WithMinScore sets the minimum score requested (0 to 1)
+func (b *RecaptchaV3TaskBuilder) WithMinScore(minScore float64) *RecaptchaV3TaskBuilder {
+	b.task.MinScore = minScore
+	return b
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *RecaptchaV3TaskBuilder) WithProxy(proxy string) *RecaptchaV3TaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *RecaptchaV3TaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, RecaptchaV3)
+}
+
+// AuroNetworkTaskBuilder builds Auro Network tasks
+type AuroNetworkTaskBuilder struct{ task CaptchaTask }
+
+// NewAuroNetworkTask starts an Auro Network task, which has no required fields
+func NewAuroNetworkTask() *AuroNetworkTaskBuilder {
+	return &AuroNetworkTaskBuilder{}
+}
+
+// WithProxy sets the proxy used to solve the task
+func (b *AuroNetworkTaskBuilder) WithProxy(proxy string) *AuroNetworkTaskBuilder {
+	b.task.Proxy = proxy
+	return b
+}
+
+// Build validates and returns the task
+func (b *AuroNetworkTaskBuilder) Build() (*CaptchaTask, error) {
+	return buildTask(b.task, AuroNetwork)
+}
+
+// buildTask validates a copy of a builder's task so the builder can be reused
+func buildTask(task CaptchaTask, captchaType CaptchaType) (*CaptchaTask, error) {
+	if err := task.Validate(captchaType); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
@@ -0,0 +1,138 @@
+package freecap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildersProduceValidTasks(t *testing.T) {
+	const (
+		sitekey = "a9b5fb07-92ff-493f-86fe-352a2803b3df"
+		siteurl = "discord.com"
+		proxy   = "http://proxy.example.com:8080"
+	)
+
+	tests := []struct {
+		name        string
+		captchaType CaptchaType
+		build       func() (*CaptchaTask, error)
+		check       func(t *testing.T, task *CaptchaTask)
+	}{
+		{"hcaptcha", HCaptcha, NewHCaptchaTask(sitekey, siteurl).WithGroqKey("gsk").WithRqData("rq").ForDiscord().WithProxy(proxy).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Sitekey != sitekey || task.Siteurl != siteurl || task.GroqAPIKey != "gsk" || task.RqData != "rq" || !task.Discord {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"captchafox", CaptchaFox, NewCaptchaFoxTask(sitekey, siteurl).WithProxy(proxy).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Sitekey != sitekey || task.Siteurl != siteurl {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"geetest", Geetest, NewGeetestTask("challenge").Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Challenge != "challenge" || task.RiskType != Slide {
+					t.Errorf("task = %+v, want slide risk type by default", task)
+				}
+			}},
+		{"geetest risk type", Geetest, NewGeetestTask("challenge").WithRiskType(Icon).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.RiskType != Icon {
+					t.Errorf("RiskType = %q, want %q", task.RiskType, Icon)
+				}
+			}},
+		{"discord id", DiscordID, NewDiscordIDTask(sitekey, siteurl).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Sitekey != sitekey || task.Siteurl != siteurl {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"funcaptcha", FunCaptcha, NewFunCaptchaTask(RobloxLogin).WithChromeVersion("140").Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Preset != RobloxLogin || task.ChromeVersion != "140" || task.Blob != NewCaptchaTask().Blob {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"turnstile", Turnstile, NewTurnstileTask(sitekey, siteurl).WithAction("login").WithCData("cdata").Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Action != "login" || task.CData != "cdata" {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"recaptcha v2", RecaptchaV2, NewRecaptchaV2Task(sitekey, siteurl).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Sitekey != sitekey || task.Siteurl != siteurl {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"recaptcha v3", RecaptchaV3, NewRecaptchaV3Task(sitekey, siteurl, "submit").WithMinScore(0.7).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Action != "submit" || task.MinScore != 0.7 {
+					t.Errorf("task = %+v", task)
+				}
+			}},
+		{"auro network", AuroNetwork, NewAuroNetworkTask().WithProxy(proxy).Build,
+			func(t *testing.T, task *CaptchaTask) {
+				if task.Proxy != proxy {
+					t.Errorf("Proxy = %q, want %q", task.Proxy, proxy)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := tt.build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if err := task.Validate(tt.captchaType); err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			tt.check(t, task)
+		})
+	}
+}
+
+func TestBuildersSurfaceValidationErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() (*CaptchaTask, error)
+	}{
+		{"hcaptcha without sitekey", NewHCaptchaTask("", "discord.com").Build},
+		{"discord hcaptcha without rqdata", NewHCaptchaTask("sitekey", "discord.com").WithGroqKey("gsk").ForDiscord().Build},
+		{"captchafox without siteurl", NewCaptchaFoxTask("sitekey", "").Build},
+		{"geetest without challenge", NewGeetestTask("").Build},
+		{"geetest invalid risk type", NewGeetestTask("challenge").WithRiskType("puzzle").Build},
+		{"funcaptcha without preset", NewFunCaptchaTask("").Build},
+		{"funcaptcha invalid blob", NewFunCaptchaTask(RobloxLogin).WithBlob("not a blob!").Build},
+		{"recaptcha v3 without action", NewRecaptchaV3Task("sitekey", "example.com", "").Build},
+		{"recaptcha v3 score out of range", NewRecaptchaV3Task("sitekey", "example.com", "submit").WithMinScore(1.5).Build},
+		{"invalid proxy", NewAuroNetworkTask().WithProxy("proxy.example.com").Build},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := tt.build()
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("Build = %v, want validation error", err)
+			}
+			if task != nil {
+				t.Errorf("Build returned task %+v alongside the error", task)
+			}
+		})
+	}
+}
+
+func TestBuilderIsReusable(t *testing.T) {
+	builder := NewTurnstileTask("sitekey", "example.com")
+	first, err := builder.WithAction("login").Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	second, err := builder.WithAction("signup").Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if first.Action != "login" || second.Action != "signup" {
+		t.Errorf("actions = %q, %q; want each Build to copy the task", first.Action, second.Action)
+	}
+}
@@ -0,0 +1,59 @@
+package freecap
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the time source used for request retries, task polling, rate
+// limiting and the circuit breaker. Tests can supply a fake (see
+// testutil.FakeClock) to drive timeouts and backoff without real delays.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at a fixed interval until stopped
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t realTicker) Stop()               { t.ticker.Stop() }
+
+// since returns the time elapsed on the client's clock since t
+func (c *FreeCapClient) since(t time.Time) time.Duration {
+	return c.clock.Now().Sub(t)
+}
+
+// sleep waits on the client's clock for d or until the context is done
+func (c *FreeCapClient) sleep(ctx context.Context, d time.Duration) error {
+	return sleepClock(ctx, c.clock, d)
+}
+
+// sleepClock waits on clock for d or until the context is done
+func sleepClock(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(d):
+		return nil
+	}
+}
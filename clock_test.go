@@ -0,0 +1,213 @@
+package freecap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	freecap "github.com/freecap-su/Wrappers"
+	"github.com/freecap-su/Wrappers/testutil"
+)
+
+// newFakeClockClient creates a client for server driven by clock
+func newFakeClockClient(t *testing.T, server *testutil.MockServer, clock *testutil.FakeClock, configure func(*freecap.ClientConfig)) *freecap.FreeCapClient {
+	t.Helper()
+	config := freecap.NewClientConfig()
+	config.Clock = clock
+	config.RetryDelay = time.Second
+	config.DefaultCheckInterval = time.Second
+	if configure != nil {
+		configure(config)
+	}
+	client, err := server.NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// waitForTimers waits for n timers on clock, failing if the solve reports
+// back on errs first
+func waitForTimers(t *testing.T, clock *testutil.FakeClock, n int, errs <-chan error) {
+	t.Helper()
+	blocked := make(chan struct{})
+	go func() {
+		clock.BlockUntil(n)
+		close(blocked)
+	}()
+	select {
+	case <-blocked:
+	case err := <-errs:
+		t.Fatalf("solve returned before waiting on the clock: %v", err)
+	}
+}
+
+func TestFakeClockAheadOfContextDeadline(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.SetPendingPolls(1000)
+
+	// The fake clock runs an hour ahead of real time; the ctx deadline, which
+	// is real time, must still leave the solve its 30 minutes
+	clock := testutil.NewFakeClock(time.Now().Add(time.Hour))
+	client := newFakeClockClient(t, server, clock, nil)
+	taskID, err := client.CreateTask(context.Background(), &freecap.CaptchaTask{Sitekey: "sitekey", Siteurl: "example.com"}, freecap.Turnstile)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.WaitForResult(ctx, taskID, 2*time.Hour, time.Second)
+		errs <- err
+	}()
+
+	waitForTimers(t, clock, 2, errs) // solve timeout and poll ticker
+	clock.Advance(time.Second)
+	deadline := time.Now().Add(5 * time.Second)
+	for server.Calls("/GetTask") < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("no poll after advancing one check interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(30 * time.Minute)
+	var timeoutErr *freecap.FreeCapTimeoutError
+	if err := <-errs; !errors.As(err, &timeoutErr) {
+		t.Fatalf("WaitForResult = %v, want *FreeCapTimeoutError", err)
+	}
+	if timeoutErr.Polls < 1 {
+		t.Errorf("Polls = %d, want the solve to have polled before timing out", timeoutErr.Polls)
+	}
+}
+
+func TestFakeClockAheadStillCreatesTask(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	// checkTimeLeft compares the ctx deadline with real time, so a clock an
+	// hour ahead does not make a 30 minute deadline look already expired
+	clock := testutil.NewFakeClock(time.Now().Add(time.Hour))
+	client := newFakeClockClient(t, server, clock, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.SolveCaptcha(ctx, &freecap.CaptchaTask{Sitekey: "sitekey", Siteurl: "example.com"}, freecap.Turnstile, 0, 0)
+		errs <- err
+	}()
+
+	waitForTimers(t, clock, 2, errs) // solve timeout and poll ticker
+	clock.Advance(time.Second)
+	if err := <-errs; err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+	if n := server.Calls("/CreateTask"); n != 1 {
+		t.Errorf("/CreateTask calls = %d, want 1", n)
+	}
+}
+
+func TestFakeClockDrivesRateLimiter(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	clock := testutil.NewFakeClock(time.Now())
+	client := newFakeClockClient(t, server, clock, func(config *freecap.ClientConfig) {
+		config.RequestsPerSecond = 1
+	})
+
+	if _, err := client.GetBalance(context.Background()); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetBalance(context.Background())
+		done <- err
+	}()
+
+	// The second request waits for a token on the fake clock, not real time
+	clock.BlockUntil(1)
+	select {
+	case err := <-done:
+		t.Fatalf("second request finished before the clock advanced: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("GetBalance after advancing: %v", err)
+	}
+	if n := server.Calls("/GetBalance"); n != 2 {
+		t.Errorf("/GetBalance calls = %d, want 2", n)
+	}
+}
+
+func TestFakeClockDrivesBreakerCooldown(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.InjectFailure("/GetBalance", 503, `{"status":false,"error":"down"}`)
+
+	clock := testutil.NewFakeClock(time.Now())
+	client := newFakeClockClient(t, server, clock, func(config *freecap.ClientConfig) {
+		config.MaxRetries = 0
+		config.BreakerThreshold = 1
+		config.BreakerCooldown = 30 * time.Second
+	})
+	ctx := context.Background()
+
+	if _, err := client.GetBalance(ctx); !errors.Is(err, freecap.ErrServerError) {
+		t.Fatalf("GetBalance = %v, want ErrServerError", err)
+	}
+	if _, err := client.GetBalance(ctx); !errors.Is(err, freecap.ErrCircuitOpen) {
+		t.Fatalf("GetBalance while open = %v, want ErrCircuitOpen", err)
+	}
+
+	clock.Advance(29 * time.Second)
+	if state := client.BreakerState(); state != freecap.BreakerOpen {
+		t.Errorf("state before the cooldown = %v, want open", state)
+	}
+	clock.Advance(time.Second)
+	if state := client.BreakerState(); state != freecap.BreakerHalfOpen {
+		t.Errorf("state after the cooldown = %v, want half-open", state)
+	}
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("probe after the cooldown = %v", err)
+	}
+	if state := client.BreakerState(); state != freecap.BreakerClosed {
+		t.Errorf("state after a successful probe = %v, want closed", state)
+	}
+}
+
+func TestFakeClockDrivesSupportedTypesTTL(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.SetSupportedTypes([]string{"hcaptcha"})
+
+	clock := testutil.NewFakeClock(time.Now())
+	client := newFakeClockClient(t, server, clock, func(config *freecap.ClientConfig) {
+		config.SupportedTypesTTL = time.Minute
+	})
+	ctx := context.Background()
+
+	if _, err := client.GetSupportedTypes(ctx); err != nil {
+		t.Fatalf("GetSupportedTypes: %v", err)
+	}
+	server.SetSupportedTypes([]string{"hcaptcha", "turnstile"})
+
+	clock.Advance(59 * time.Second)
+	if types, err := client.GetSupportedTypes(ctx); err != nil || len(types) != 1 {
+		t.Errorf("GetSupportedTypes before the TTL = %v, %v; want the cached list", types, err)
+	}
+	clock.Advance(time.Second)
+	if types, err := client.GetSupportedTypes(ctx); err != nil || len(types) != 2 {
+		t.Errorf("GetSupportedTypes after the TTL = %v, %v; want the new list", types, err)
+	}
+}
@@ -0,0 +1,78 @@
+package freecap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// defaultCompressThreshold is the smallest request body compressed when
+// CompressRequests is set and CompressThreshold is not
+const defaultCompressThreshold = 1 << 10
+
+// shouldCompress reports whether a request body is large enough to gzip
+func (c *FreeCapClient) shouldCompress(body []byte) bool {
+	if !c.config.CompressRequests {
+		return false
+	}
+	threshold := c.config.CompressThreshold
+	if threshold <= 0 {
+		threshold = defaultCompressThreshold
+	}
+	return len(body) >= threshold
+}
+
+// compressBody gzips a request body
+func compressBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressBody wraps a response body according to its Content-Encoding.
+// Unknown or identity encodings are returned unchanged. The returned closer
+// releases the decompressor, not the underlying body.
+func decompressBody(resp *http.Response) (io.Reader, func() error, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	switch encoding {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decompress gzip response body: %w", err)
+		}
+		return zr, zr.Close, nil
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send raw deflate
+		br := bufio.NewReader(resp.Body)
+		header, err := br.Peek(2)
+		if err == nil && isZlibHeader(header) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to decompress deflate response body: %w", err)
+			}
+			return zr, zr.Close, nil
+		}
+		fr := flate.NewReader(br)
+		return fr, fr.Close, nil
+	default:
+		return resp.Body, func() error { return nil }, nil
+	}
+}
+
+// isZlibHeader reports whether the first two bytes of a stream form a zlib header
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
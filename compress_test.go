@@ -0,0 +1,116 @@
+package freecap
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// encodedDoer answers every request with a 200 and a fixed, already encoded
+// body, bypassing the transport's own gzip handling
+type encodedDoer struct {
+	encoding string
+	body     []byte
+}
+
+func (d encodedDoer) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}, "Content-Encoding": []string{d.encoding}},
+		Body:       io.NopCloser(bytes.NewReader(d.body)),
+		Request:    req,
+	}, nil
+}
+
+// encode compresses body with the named Content-Encoding
+func encode(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "flate":
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w = fw
+	default:
+		t.Fatalf("unknown encoding %q", encoding)
+	}
+	if _, err := w.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompressedResponses(t *testing.T) {
+	body := []byte(`{"status":true,"balance":4.5}`)
+	tests := []struct {
+		name     string
+		encoding string
+		data     []byte
+	}{
+		{"gzip", "gzip", encode(t, "gzip", body)},
+		{"x-gzip", "x-gzip", encode(t, "gzip", body)},
+		{"zlib deflate", "deflate", encode(t, "zlib", body)},
+		{"raw deflate", "deflate", encode(t, "flate", body)},
+		{"identity", "identity", body},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, unusedAPIURL, nil)
+			client.client = encodedDoer{encoding: tt.encoding, body: tt.data}
+
+			balance, err := client.GetBalance(context.Background())
+			if err != nil {
+				t.Fatalf("GetBalance: %v", err)
+			}
+			if balance != 4.5 {
+				t.Errorf("balance = %v, want 4.5", balance)
+			}
+		})
+	}
+}
+
+func TestResponseLimitAppliesToDecompressedSize(t *testing.T) {
+	body := []byte(`{"status":true,"balance":1,"padding":"` + strings.Repeat("x", 64<<10) + `"}`)
+	compressed := encode(t, "gzip", body)
+	if len(compressed) >= 4096 {
+		t.Fatalf("compressed body is %d bytes; the test needs it under the limit", len(compressed))
+	}
+
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.MaxResponseBytes = 4096
+		config.MaxRetries = 0
+	})
+	client.client = encodedDoer{encoding: "gzip", body: compressed}
+
+	if _, err := client.GetBalance(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("GetBalance = %v, want ErrResponseTooLarge for a %d byte body compressed to %d", err, len(body), len(compressed))
+	}
+}
+
+func TestCorruptGzipResponse(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.MaxRetries = 0
+	})
+	client.client = encodedDoer{encoding: "gzip", body: []byte("not gzip")}
+
+	if _, err := client.GetBalance(context.Background()); err == nil || !strings.Contains(err.Error(), "decompress") {
+		t.Errorf("GetBalance = %v, want a decompression error", err)
+	}
+}
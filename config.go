@@ -0,0 +1,323 @@
+package freecap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by ConfigFromEnv and APIKeyFromEnv
+const (
+	EnvAPIKey            = "FREECAP_API_KEY"
+	EnvAPIURL            = "FREECAP_API_URL"
+	EnvTimeout           = "FREECAP_TIMEOUT"
+	EnvMaxRetries        = "FREECAP_MAX_RETRIES"
+	EnvRetryDelay        = "FREECAP_RETRY_DELAY"
+	EnvTaskTimeout       = "FREECAP_TASK_TIMEOUT"
+	EnvCheckInterval     = "FREECAP_CHECK_INTERVAL"
+	EnvUserAgent         = "FREECAP_USER_AGENT"
+	EnvRequestsPerSecond = "FREECAP_REQUESTS_PER_SECOND"
+)
+
+// ConfigFromEnv builds a client configuration from FREECAP_* environment
+// variables, using defaults for unset ones. Durations accept Go duration
+// strings ("30s", "1m") or a plain number of seconds. The result is
+// validated like LoadConfig.
+func ConfigFromEnv() (*ClientConfig, error) {
+	config := NewClientConfig()
+	if err := applyEnv(config); err != nil {
+		return nil, err
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// APIKeyFromEnv reads the API key from FREECAP_API_KEY
+func APIKeyFromEnv() (string, error) {
+	apiKey := strings.TrimSpace(os.Getenv(EnvAPIKey))
+	if apiKey == "" {
+		return "", NewFreeCapValidationError(EnvAPIKey + " is not set")
+	}
+	return apiKey, nil
+}
+
+// applyEnv overrides config fields with any FREECAP_* variables that are set
+func applyEnv(config *ClientConfig) error {
+	if value, ok := lookupEnv(EnvAPIURL); ok {
+		config.APIURL = value
+	}
+	if value, ok := lookupEnv(EnvUserAgent); ok {
+		config.UserAgent = value
+	}
+
+	durations := []struct {
+		name   string
+		target *time.Duration
+	}{
+		{EnvTimeout, &config.RequestTimeout},
+		{EnvRetryDelay, &config.RetryDelay},
+		{EnvTaskTimeout, &config.DefaultTaskTimeout},
+		{EnvCheckInterval, &config.DefaultCheckInterval},
+	}
+	for _, d := range durations {
+		value, ok := lookupEnv(d.name)
+		if !ok {
+			continue
+		}
+		parsed, err := parseDuration(value)
+		if err != nil {
+			return NewFreeCapValidationError(fmt.Sprintf("invalid %s: %v", d.name, err))
+		}
+		*d.target = parsed
+	}
+
+	if value, ok := lookupEnv(EnvMaxRetries); ok {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return NewFreeCapValidationError(fmt.Sprintf("invalid %s: %q", EnvMaxRetries, value))
+		}
+		config.MaxRetries = retries
+	}
+
+	if value, ok := lookupEnv(EnvRequestsPerSecond); ok {
+		rps, err := strconv.ParseFloat(value, 64)
+		if err != nil || rps < 0 || math.IsNaN(rps) || math.IsInf(rps, 0) {
+			return NewFreeCapValidationError(fmt.Sprintf("invalid %s: %q", EnvRequestsPerSecond, value))
+		}
+		config.RequestsPerSecond = rps
+	}
+
+	return nil
+}
+
+// lookupEnv returns a trimmed environment variable, treating blank as unset
+func lookupEnv(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(name))
+	return value, value != ""
+}
+
+// parseDuration parses a Go duration string or a plain number of seconds
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		if seconds < 0 {
+			return 0, fmt.Errorf("duration cannot be negative: %q", value)
+		}
+		if seconds > math.MaxInt64/float64(time.Second) {
+			return 0, fmt.Errorf("duration too large: %q", value)
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration cannot be negative: %q", value)
+	}
+	return d, nil
+}
+
+// LoadConfig reads a JSON client configuration file. Fields missing from the
+// file keep their defaults; durations are Go duration strings such as "30s"
+// or a number of seconds. Unknown fields are rejected to catch typos.
+func LoadConfig(path string) (*ClientConfig, error) {
+	config := NewClientConfig()
+	if err := applyFile(config, path); err != nil {
+		return nil, err
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadConfigWithEnv reads a JSON configuration file and then applies
+// FREECAP_* environment variables on top. Precedence, lowest to highest:
+// defaults, file, environment.
+func LoadConfigWithEnv(path string) (*ClientConfig, error) {
+	config := NewClientConfig()
+	if err := applyFile(config, path); err != nil {
+		return nil, err
+	}
+	if err := applyEnv(config); err != nil {
+		return nil, err
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// fileConfig is the on-disk JSON representation of ClientConfig
+type fileConfig struct {
+	APIURL               *string       `json:"api_url"`
+	RequestTimeout       *jsonDuration `json:"request_timeout"`
+	MaxRetries           *int          `json:"max_retries"`
+	RetryDelay           *jsonDuration `json:"retry_delay"`
+	DefaultTaskTimeout   *jsonDuration `json:"task_timeout"`
+	DefaultCheckInterval *jsonDuration `json:"check_interval"`
+	UserAgent            *string       `json:"user_agent"`
+	MaxRetryAfter        *jsonDuration `json:"max_retry_after"`
+	MaxRetryDelay        *jsonDuration `json:"max_retry_delay"`
+	MaxRetryElapsed      *jsonDuration `json:"max_retry_elapsed"`
+	PerAttemptTimeout    *jsonDuration `json:"per_attempt_timeout"`
+	BreakerThreshold     *int          `json:"breaker_threshold"`
+	BreakerCooldown      *jsonDuration `json:"breaker_cooldown"`
+	RequestsPerSecond    *float64      `json:"requests_per_second"`
+	RateLimitBurst       *int          `json:"rate_limit_burst"`
+	MaxIdleConns         *int          `json:"max_idle_conns"`
+	MaxIdleConnsPerHost  *int          `json:"max_idle_conns_per_host"`
+	IdleConnTimeout      *jsonDuration `json:"idle_conn_timeout"`
+}
+
+// jsonDuration decodes a duration from a string like "30s" or a number of seconds
+type jsonDuration time.Duration
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	var parsed time.Duration
+	var err error
+	switch v := value.(type) {
+	case string:
+		parsed, err = parseDuration(strings.TrimSpace(v))
+	case float64:
+		parsed, err = parseDuration(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		err = fmt.Errorf("invalid duration %s", string(data))
+	}
+	if err != nil {
+		return err
+	}
+
+	*d = jsonDuration(parsed)
+	return nil
+}
+
+// applyFile overrides config fields with the values present in a JSON file
+func applyFile(config *ClientConfig, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fc fileConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fc); err != nil {
+		return NewFreeCapValidationError(fmt.Sprintf("invalid config file %s: %v", path, err))
+	}
+
+	setString := func(target *string, value *string) {
+		if value != nil {
+			*target = strings.TrimSpace(*value)
+		}
+	}
+	setDuration := func(target *time.Duration, value *jsonDuration) {
+		if value != nil {
+			*target = time.Duration(*value)
+		}
+	}
+	setInt := func(target *int, value *int) {
+		if value != nil {
+			*target = *value
+		}
+	}
+
+	setString(&config.APIURL, fc.APIURL)
+	setString(&config.UserAgent, fc.UserAgent)
+	setDuration(&config.RequestTimeout, fc.RequestTimeout)
+	setDuration(&config.RetryDelay, fc.RetryDelay)
+	setDuration(&config.DefaultTaskTimeout, fc.DefaultTaskTimeout)
+	setDuration(&config.DefaultCheckInterval, fc.DefaultCheckInterval)
+	setDuration(&config.MaxRetryAfter, fc.MaxRetryAfter)
+	setDuration(&config.MaxRetryDelay, fc.MaxRetryDelay)
+	setDuration(&config.MaxRetryElapsed, fc.MaxRetryElapsed)
+	setDuration(&config.PerAttemptTimeout, fc.PerAttemptTimeout)
+	setDuration(&config.BreakerCooldown, fc.BreakerCooldown)
+	setDuration(&config.IdleConnTimeout, fc.IdleConnTimeout)
+	setInt(&config.MaxRetries, fc.MaxRetries)
+	setInt(&config.BreakerThreshold, fc.BreakerThreshold)
+	setInt(&config.RateLimitBurst, fc.RateLimitBurst)
+	setInt(&config.MaxIdleConns, fc.MaxIdleConns)
+	setInt(&config.MaxIdleConnsPerHost, fc.MaxIdleConnsPerHost)
+	if fc.RequestsPerSecond != nil {
+		config.RequestsPerSecond = *fc.RequestsPerSecond
+	}
+
+	return nil
+}
+
+// validateConfig checks a loaded configuration for obviously invalid values
+func validateConfig(config *ClientConfig) error {
+	if _, err := normalizeAPIURL(config.APIURL); err != nil {
+		return err
+	}
+	if config.RequestTimeout <= 0 {
+		return NewFreeCapValidationError("request_timeout must be positive")
+	}
+	if config.DefaultTaskTimeout <= 0 {
+		return NewFreeCapValidationError("task_timeout must be positive")
+	}
+	if config.DefaultCheckInterval <= 0 {
+		return NewFreeCapValidationError("check_interval must be positive")
+	}
+	if config.MaxRetries < 0 {
+		return NewFreeCapValidationError("max_retries cannot be negative")
+	}
+	if config.RequestsPerSecond < 0 {
+		return NewFreeCapValidationError("requests_per_second cannot be negative")
+	}
+	if math.IsNaN(config.RequestsPerSecond) || math.IsInf(config.RequestsPerSecond, 0) {
+		return NewFreeCapValidationError("requests_per_second must be a finite number")
+	}
+	return nil
+}
+
+// normalizeAPIURL validates an API base URL and returns it without trailing
+// slashes. The URL must be absolute http or https with a host; a path prefix
+// is kept, while queries, fragments and whitespace are rejected.
+func normalizeAPIURL(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", NewFreeCapValidationError("API URL cannot be empty")
+	}
+	if strings.ContainsAny(raw, " \t\r\n") {
+		return "", NewFreeCapValidationError(fmt.Sprintf("API URL %q contains whitespace", raw))
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", NewFreeCapValidationError(fmt.Sprintf("invalid API URL %q: %v", raw, err))
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", NewFreeCapValidationError(fmt.Sprintf("API URL %q must start with http:// or https://", raw))
+	}
+	if u.Hostname() == "" {
+		return "", NewFreeCapValidationError(fmt.Sprintf("API URL %q has no host", raw))
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", NewFreeCapValidationError(fmt.Sprintf("API URL %q cannot have a query or fragment", raw))
+	}
+	if u.User != nil {
+		return "", NewFreeCapValidationError(fmt.Sprintf("API URL %q cannot contain credentials", redactProxy(raw)))
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
@@ -0,0 +1,112 @@
+package freecap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSupportedTypesTTL is how long GetSupportedTypes caches its result
+// when SupportedTypesTTL is not set
+const defaultSupportedTypesTTL = 5 * time.Minute
+
+// supportedTypesCache holds the last GetSupportedTypes result and the fetch
+// in progress, if any
+type supportedTypesCache struct {
+	mu        sync.Mutex
+	types     []string
+	fetchedAt time.Time
+	fetch     *supportedTypesFetch
+}
+
+// supportedTypesFetch is a /GetSupportedTypes request shared by concurrent
+// callers; types and err are set before done is closed
+type supportedTypesFetch struct {
+	done  chan struct{}
+	types []string
+	err   error
+}
+
+// GetSupportedTypes returns the captcha types the server currently supports,
+// from the /GetSupportedTypes endpoint. The list is cached for
+// SupportedTypesTTL (0 = 5 minutes), and concurrent callers share one
+// request while it is refreshed.
+func (c *FreeCapClient) GetSupportedTypes(ctx context.Context) ([]string, error) {
+	ttl := c.config.SupportedTypesTTL
+	if ttl <= 0 {
+		ttl = defaultSupportedTypesTTL
+	}
+
+	cache := &c.supportedTypes
+	cache.mu.Lock()
+	if cache.types != nil && c.clock.Now().Sub(cache.fetchedAt) < ttl {
+		types := append([]string(nil), cache.types...)
+		cache.mu.Unlock()
+		return types, nil
+	}
+	if fetch := cache.fetch; fetch != nil {
+		cache.mu.Unlock()
+		select {
+		case <-fetch.done:
+			return append([]string(nil), fetch.types...), fetch.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	fetch := &supportedTypesFetch{done: make(chan struct{})}
+	cache.fetch = fetch
+	cache.mu.Unlock()
+
+	// The lock is not held during the request, so a slow server does not
+	// block callers that would be served from the cache
+	fetch.types, fetch.err = c.fetchSupportedTypes(ctx)
+
+	cache.mu.Lock()
+	if fetch.err == nil {
+		cache.types = fetch.types
+		cache.fetchedAt = c.clock.Now()
+	}
+	cache.fetch = nil
+	cache.mu.Unlock()
+	close(fetch.done)
+
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+	return append([]string(nil), fetch.types...), nil
+}
+
+// fetchSupportedTypes requests the supported captcha types from the server
+func (c *FreeCapClient) fetchSupportedTypes(ctx context.Context) ([]string, error) {
+	c.logger.Debug("Fetching supported captcha types")
+
+	response, err := c.makeRequest(ctx, "POST", "/GetSupportedTypes", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	if status, exists := response[c.schema.Status]; exists && status != true {
+		errorMsg := "Unknown error getting supported types"
+		if errStr, ok := c.schema.errorMessage(response); ok {
+			errorMsg = errStr
+		}
+		return nil, NewFreeCapAPIError(fmt.Sprintf("Failed to get supported types: %s", errorMsg), 0, response)
+	}
+
+	list, ok := response["types"].([]interface{})
+	if !ok {
+		return nil, NewFreeCapAPIError("No supported types in response", 0, response)
+	}
+
+	types := make([]string, 0, len(list))
+	for _, item := range list {
+		name, ok := item.(string)
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, NewFreeCapAPIError(fmt.Sprintf("Invalid captcha type in response: %v", item), 0, response)
+		}
+		types = append(types, strings.TrimSpace(name))
+	}
+	return types, nil
+}
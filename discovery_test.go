@@ -0,0 +1,137 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetSupportedTypes(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetSupportedTypes", respond(200, map[string]interface{}{
+		"status": true,
+		"types":  []interface{}{"hcaptcha", " turnstile "},
+	}))
+	client := newTestClient(t, api.URL, nil)
+
+	types, err := client.GetSupportedTypes(context.Background())
+	if err != nil {
+		t.Fatalf("GetSupportedTypes: %v", err)
+	}
+	if want := []string{"hcaptcha", "turnstile"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("types = %v, want %v", types, want)
+	}
+
+	// A cached result is served without a second request, and callers
+	// cannot modify the cache through the returned slice
+	types[0] = "changed"
+	again, err := client.GetSupportedTypes(context.Background())
+	if err != nil {
+		t.Fatalf("GetSupportedTypes (cached): %v", err)
+	}
+	if again[0] != "hcaptcha" {
+		t.Errorf("cached types = %v, modified through an earlier result", again)
+	}
+	if n := api.calls("/GetSupportedTypes"); n != 1 {
+		t.Errorf("/GetSupportedTypes calls = %d, want 1", n)
+	}
+}
+
+func TestGetSupportedTypesTTLExpires(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetSupportedTypes", respond(200, map[string]interface{}{"status": true, "types": []interface{}{"hcaptcha"}}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.SupportedTypesTTL = 20 * time.Millisecond
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetSupportedTypes(context.Background()); err != nil {
+			t.Fatalf("GetSupportedTypes: %v", err)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.GetSupportedTypes(context.Background()); err != nil {
+		t.Fatalf("GetSupportedTypes after the TTL: %v", err)
+	}
+	if n := api.calls("/GetSupportedTypes"); n != 2 {
+		t.Errorf("/GetSupportedTypes calls = %d, want 2 (one per TTL)", n)
+	}
+}
+
+func TestGetSupportedTypesSharesRefresh(t *testing.T) {
+	release := make(chan struct{})
+	api := newFakeAPI(t)
+	api.handle("/GetSupportedTypes", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		<-release
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "types": []interface{}{"hcaptcha"}})
+	})
+	client := newTestClient(t, api.URL, nil)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			types, err := client.GetSupportedTypes(context.Background())
+			if err == nil && len(types) != 1 {
+				err = fmt.Errorf("types = %v, want [hcaptcha]", types)
+			}
+			errs <- err
+		}()
+	}
+	waitForCalls(t, api, "/GetSupportedTypes", 1)
+
+	// A caller giving up does not wait for the request in progress
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetSupportedTypes(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetSupportedTypes with an expiring ctx = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetSupportedTypes: %v", err)
+		}
+	}
+	if n := api.calls("/GetSupportedTypes"); n != 1 {
+		t.Errorf("/GetSupportedTypes calls = %d, want 1 shared by all callers", n)
+	}
+}
+
+func TestGetSupportedTypesInvalidResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+	}{
+		{"failed status", map[string]interface{}{"status": false, "error": "maintenance"}},
+		{"missing types", map[string]interface{}{"status": true}},
+		{"non-string type", map[string]interface{}{"status": true, "types": []interface{}{"hcaptcha", 3}}},
+		{"blank type", map[string]interface{}{"status": true, "types": []interface{}{" "}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.handle("/GetSupportedTypes", respond(200, tt.response))
+			client := newTestClient(t, api.URL, nil)
+
+			if _, err := client.GetSupportedTypes(context.Background()); err == nil {
+				t.Error("GetSupportedTypes succeeded on an invalid response")
+			}
+			// Failures are not cached
+			_, _ = client.GetSupportedTypes(context.Background())
+			if n := api.calls("/GetSupportedTypes"); n != 2 {
+				t.Errorf("/GetSupportedTypes calls = %d, want 2", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package freecap
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Canned values returned in DryRun mode
+const (
+	DryRunSolution     = "dry-run-solution"
+	DryRunTaskIDPrefix = "dry-run-"
+)
+
+// dryRunTaskSeq numbers the synthetic task IDs handed out in DryRun mode
+var dryRunTaskSeq atomic.Uint64
+
+// dryRunResponse logs a request that DryRun mode does not send and returns
+// the response the API would give for a successful call
+func (c *FreeCapClient) dryRunResponse(method, endpoint string, data map[string]interface{}) map[string]interface{} {
+	c.logger.Info("[DRY RUN] Not sending %s %s: %+v", method, endpoint, redactSensitive(data))
+
+	response := map[string]interface{}{c.schema.Status: true}
+	switch strings.TrimLeft(endpoint, "/") {
+	case "CreateTask":
+		response[c.schema.TaskID] = fmt.Sprintf("%s%d", DryRunTaskIDPrefix, dryRunTaskSeq.Add(1))
+	case "GetTask":
+		response[c.schema.Status] = string(Solved)
+		response[c.schema.Solution] = DryRunSolution
+	case "GetBalance":
+		response["balance"] = 0.0
+	case "GetSupportedTypes":
+		response["types"] = []interface{}{
+			string(HCaptcha), string(CaptchaFox), string(Geetest), string(DiscordID), string(FunCaptcha),
+			string(Turnstile), string(RecaptchaV2), string(RecaptchaV3), string(AuroNetwork),
+		}
+	}
+	return response
+}
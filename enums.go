@@ -0,0 +1,68 @@
+package freecap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func (t CaptchaType) String() string { return string(t) }
+
+func (s TaskStatus) String() string { return string(s) }
+
+func (r RiskType) String() string { return string(r) }
+
+func (p FunCaptchaPreset) String() string { return string(p) }
+
+// valid reports whether s is one of the known task statuses
+func (s TaskStatus) valid() bool {
+	switch s {
+	case Pending, Processing, Solved, Error, Failed:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON rejects unknown task statuses
+func (s TaskStatus) MarshalJSON() ([]byte, error) {
+	if s != "" && !s.valid() {
+		return nil, fmt.Errorf("freecap: invalid task status %q", string(s))
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON rejects unknown task statuses, matching known ones in any case
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("freecap: task status must be a string: %w", err)
+	}
+	status := TaskStatus(strings.ToLower(value))
+	if status != "" && !status.valid() {
+		return fmt.Errorf("freecap: invalid task status %q (use pending, processing, solved, error or failed)", value)
+	}
+	*s = status
+	return nil
+}
+
+// MarshalJSON rejects unknown risk types
+func (r RiskType) MarshalJSON() ([]byte, error) {
+	if r != "" && !r.valid() {
+		return nil, fmt.Errorf("freecap: invalid risk type %q", string(r))
+	}
+	return json.Marshal(string(r))
+}
+
+// UnmarshalJSON rejects unknown risk types
+func (r *RiskType) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("freecap: risk type must be a string: %w", err)
+	}
+	riskType := RiskType(value)
+	if riskType != "" && !riskType.valid() {
+		return fmt.Errorf("freecap: invalid risk type %q (use slide, gobang, icon or ai)", value)
+	}
+	*r = riskType
+	return nil
+}
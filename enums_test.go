@@ -0,0 +1,87 @@
+package freecap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTaskStatusJSONRoundTrip(t *testing.T) {
+	for _, status := range []TaskStatus{Pending, Processing, Solved, Error, Failed, ""} {
+		t.Run(status.String(), func(t *testing.T) {
+			data, err := json.Marshal(status)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got TaskStatus
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if got != status {
+				t.Errorf("round trip = %q, want %q", got, status)
+			}
+		})
+	}
+}
+
+func TestTaskStatusUnmarshalIgnoresCase(t *testing.T) {
+	tests := map[string]TaskStatus{
+		`"Solved"`:     Solved,
+		`"PROCESSING"`: Processing,
+		`"Failed"`:     Failed,
+	}
+	for data, want := range tests {
+		var got TaskStatus
+		if err := json.Unmarshal([]byte(data), &got); err != nil {
+			t.Errorf("Unmarshal(%s): %v", data, err)
+		} else if got != want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestRiskTypeJSONRoundTrip(t *testing.T) {
+	for _, riskType := range []RiskType{Slide, Gobang, Icon, AI, ""} {
+		t.Run(riskType.String(), func(t *testing.T) {
+			data, err := json.Marshal(riskType)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got RiskType
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if got != riskType {
+				t.Errorf("round trip = %q, want %q", got, riskType)
+			}
+		})
+	}
+}
+
+func TestEnumJSONRejectsInvalid(t *testing.T) {
+	if _, err := json.Marshal(TaskStatus("done")); err == nil {
+		t.Error("Marshal of an unknown task status succeeded")
+	}
+	if _, err := json.Marshal(RiskType("puzzle")); err == nil {
+		t.Error("Marshal of an unknown risk type succeeded")
+	}
+
+	for _, data := range []string{`"done"`, `3`} {
+		var status TaskStatus
+		if err := json.Unmarshal([]byte(data), &status); err == nil {
+			t.Errorf("Unmarshal(%s) into TaskStatus succeeded with %q", data, status)
+		}
+	}
+	for _, data := range []string{`"puzzle"`, `true`} {
+		var riskType RiskType
+		if err := json.Unmarshal([]byte(data), &riskType); err == nil {
+			t.Errorf("Unmarshal(%s) into RiskType succeeded with %q", data, riskType)
+		}
+	}
+
+	var task struct {
+		RiskType RiskType `json:"risk_type"`
+	}
+	if err := json.Unmarshal([]byte(`{"risk_type":"puzzle"}`), &task); err == nil {
+		t.Error("Unmarshal of a struct with an unknown risk type succeeded")
+	}
+}
@@ -0,0 +1,38 @@
+package freecap_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	freecap "github.com/freecap-su/Wrappers"
+)
+
+func ExampleNewFreeCapClient() {
+	config := freecap.NewClientConfig()
+	config.DryRun = true // answer locally instead of calling the API
+	config.DefaultCheckInterval = 10 * time.Millisecond
+
+	client, err := freecap.NewFreeCapClient("your-api-key", config, &freecap.NullLogger{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer client.Close()
+
+	task := &freecap.CaptchaTask{
+		Sitekey: "0x4AAAAAAABkMYinukE8nzY",
+		Siteurl: "https://example.com/login",
+	}
+	outcome, err := client.SolveCaptchaWithMeta(context.Background(), task, freecap.Turnstile, 0, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(outcome.Solution)
+	fmt.Println(strings.HasPrefix(outcome.TaskID, freecap.DryRunTaskIDPrefix))
+	// Output:
+	// dry-run-solution
+	// true
+}
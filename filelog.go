@@ -0,0 +1,175 @@
+package freecap
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxLogFileBytes is the rotation size used when maxBytes is not set
+const defaultMaxLogFileBytes = 10 << 20
+
+// FileLogger writes log lines to a file, rotating it once it grows past a
+// size limit and keeping a fixed number of backups (path.1 is the newest).
+// Writes are buffered; Warning and Error messages flush immediately and
+// Close flushes the rest. It is safe for concurrent use.
+type FileLogger struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	backups  int
+	file     *os.File
+	buf      *bufio.Writer
+	size     int64
+	closed   bool
+}
+
+// NewFileLogger opens (or creates) the log file at path. It rotates the file
+// when it would exceed maxBytes (0 = 10 MiB), keeping up to backups old files.
+func NewFileLogger(path string, maxBytes int64, backups int) (*FileLogger, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, NewFreeCapValidationError("Log file path cannot be empty")
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxLogFileBytes
+	}
+	if backups < 0 {
+		return nil, NewFreeCapValidationError("Log file backups cannot be negative")
+	}
+
+	f := &FileLogger{path: path, maxBytes: maxBytes, backups: backups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the log file for appending and records its current size
+func (f *FileLogger) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.buf = bufio.NewWriter(file)
+	f.size = info.Size()
+	return nil
+}
+
+// rotate closes the current file, shifts the backups and starts a new file.
+// The log file is reopened even if shifting fails; file is nil only when it
+// cannot be reopened.
+func (f *FileLogger) rotate() error {
+	_ = f.buf.Flush()
+	_ = f.file.Close()
+
+	shiftErr := f.shiftBackups()
+	if err := f.open(); err != nil {
+		f.file = nil
+		return err
+	}
+	return shiftErr
+}
+
+// shiftBackups renames path to path.1, path.1 to path.2 and so on, dropping
+// the oldest backup. With no backups the file is removed.
+func (f *FileLogger) shiftBackups() error {
+	if f.backups == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	for i := f.backups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(f.path, i), backupPath(f.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(f.path, backupPath(f.path, 1))
+}
+
+// backupPath returns the path of the n-th backup of a log file
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func (f *FileLogger) log(level LogLevel, message string, args ...interface{}) {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	line := fmt.Sprintf("%s [%s] %s\n", time.Now().Format("2006/01/02 15:04:05"), level, message)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return
+	}
+	if f.size > 0 && f.size+int64(len(line)) > f.maxBytes {
+		if err := f.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "freecap_client: failed to rotate log file %s: %v\n", f.path, err)
+			if f.file == nil {
+				f.closed = true
+				return
+			}
+		}
+	}
+
+	n, _ := f.buf.WriteString(line)
+	f.size += int64(n)
+	if level >= LevelWarning {
+		_ = f.buf.Flush()
+	}
+}
+
+// Flush writes any buffered log lines to the file
+func (f *FileLogger) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	return f.buf.Flush()
+}
+
+// Close flushes buffered lines and closes the file. Later messages are dropped.
+func (f *FileLogger) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+
+	flushErr := f.buf.Flush()
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	return flushErr
+}
+
+func (f *FileLogger) Debug(message string, args ...interface{}) {
+	f.log(LevelDebug, message, args...)
+}
+
+func (f *FileLogger) Info(message string, args ...interface{}) {
+	f.log(LevelInfo, message, args...)
+}
+
+func (f *FileLogger) Warning(message string, args ...interface{}) {
+	f.log(LevelWarning, message, args...)
+}
+
+func (f *FileLogger) Error(message string, args ...interface{}) {
+	f.log(LevelError, message, args...)
+}
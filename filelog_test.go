@@ -0,0 +1,82 @@
+package freecap
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readLogFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s): %v", path, err)
+	}
+	return string(data)
+}
+
+func TestFileLoggerRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freecap.log")
+	logger, err := NewFileLogger(path, 150, 2)
+	if err != nil {
+		t.Fatalf("NewFileLogger: %v", err)
+	}
+
+	// Each line is about 66 bytes, so every third line starts a new file
+	for i := 1; i <= 7; i++ {
+		logger.Info("message number %d padded out to length", i)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	current := readLogFile(t, path)
+	newest := readLogFile(t, path+".1")
+	oldest := readLogFile(t, path+".2")
+	if !strings.Contains(current, "message number 7") {
+		t.Errorf("%s = %q, want the last message", path, current)
+	}
+	if !strings.Contains(newest, "message number 5") || !strings.Contains(newest, "message number 6") {
+		t.Errorf("%s.1 = %q, want messages 5 and 6", path, newest)
+	}
+	if !strings.Contains(oldest, "message number 3") || !strings.Contains(oldest, "message number 4") {
+		t.Errorf("%s.2 = %q, want messages 3 and 4", path, oldest)
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s.3 exists beyond the backup limit: %v", path, err)
+	}
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		if info, err := os.Stat(p); err == nil && info.Size() > 150 {
+			t.Errorf("%s is %d bytes, over the 150 byte limit", p, info.Size())
+		}
+	}
+}
+
+func TestFileLoggerFlushesWarnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freecap.log")
+	logger, err := NewFileLogger(path, 0, 1)
+	if err != nil {
+		t.Fatalf("NewFileLogger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("buffered")
+	if got := readLogFile(t, path); got != "" {
+		t.Errorf("Info was written before a flush: %q", got)
+	}
+	logger.Warning("flushed")
+	if got := readLogFile(t, path); !strings.Contains(got, "[INFO] buffered") || !strings.Contains(got, "[WARNING] flushed") {
+		t.Errorf("file after Warning = %q, want both lines", got)
+	}
+}
+
+func TestFileLoggerRejectsInvalidArguments(t *testing.T) {
+	if _, err := NewFileLogger(" ", 0, 1); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewFileLogger(empty path) = %v, want validation error", err)
+	}
+	if _, err := NewFileLogger(filepath.Join(t.TempDir(), "freecap.log"), 0, -1); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewFileLogger(negative backups) = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package freecapotel records FreeCap client spans with OpenTelemetry.
+//
+// It is a separate module so the core client does not depend on the
+// OpenTelemetry API:
+//
+//	config := freecap.NewClientConfig()
+//	config.Tracer = freecapotel.NewTracer(otel.GetTracerProvider())
+package freecapotel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	freecap "github.com/freecap-su/Wrappers"
+)
+
+// instrumentationName identifies the spans created by this package
+const instrumentationName = "github.com/freecap-su/Wrappers/freecapotel"
+
+// Tracer implements freecap.Tracer on an OpenTelemetry tracer
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a Tracer from the given provider
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+// Start implements freecap.Tracer
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, freecap.Span) {
+	kind := trace.SpanKindInternal
+	if name == freecap.SpanRequest {
+		kind = trace.SpanKindClient
+	}
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(kind))
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan adapts a trace.Span to freecap.Span
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttribute(key string, value interface{}) {
+	var kv attribute.KeyValue
+	switch v := value.(type) {
+	case string:
+		kv = attribute.String(key, v)
+	case int:
+		kv = attribute.Int(key, v)
+	case int64:
+		kv = attribute.Int64(key, v)
+	case float64:
+		kv = attribute.Float64(key, v)
+	case bool:
+		kv = attribute.Bool(key, v)
+	default:
+		kv = attribute.String(key, fmt.Sprint(v))
+	}
+	s.span.SetAttributes(kv)
+}
+
+func (s *otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,131 @@
+package freecapotel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	freecap "github.com/freecap-su/Wrappers"
+	"github.com/freecap-su/Wrappers/testutil"
+)
+
+// newTracedClient returns a client on a mock server recording spans into
+// the returned recorder
+func newTracedClient(t *testing.T, server *testutil.MockServer) (*freecap.FreeCapClient, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	config := freecap.NewClientConfig()
+	config.RetryDelay = time.Millisecond
+	config.DefaultCheckInterval = time.Millisecond
+	config.Tracer = NewTracer(provider)
+	client, err := server.NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client, recorder
+}
+
+// attrs returns the attributes of a span as a map
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func hcaptchaTask() *freecap.CaptchaTask {
+	return &freecap.CaptchaTask{
+		Sitekey: "a9b5fb07-92ff-493f-86fe-352a2803b3df",
+		Siteurl: "discord.com",
+		Proxy:   "http://proxy.example.com:8080",
+	}
+}
+
+func TestTracerRecordsSolveSpans(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	client, recorder := newTracedClient(t, server)
+
+	if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), freecap.HCaptcha, 0, 0); err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+
+	byName := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		byName[span.Name()] = append(byName[span.Name()], span)
+	}
+	for _, name := range []string{freecap.SpanSolveCaptcha, freecap.SpanCreateTask, freecap.SpanGetTaskResult, freecap.SpanRequest} {
+		if len(byName[name]) == 0 {
+			t.Fatalf("no %s span recorded; got %v", name, byName)
+		}
+	}
+
+	solve := byName[freecap.SpanSolveCaptcha][0]
+	solveAttrs := attrs(solve)
+	if got := solveAttrs[freecap.AttrCaptchaType].AsString(); got != string(freecap.HCaptcha) {
+		t.Errorf("%s = %q, want hcaptcha", freecap.AttrCaptchaType, got)
+	}
+	if got := solveAttrs[freecap.AttrTaskID].AsString(); got != "mock-task-1" {
+		t.Errorf("%s = %q, want mock-task-1", freecap.AttrTaskID, got)
+	}
+	if solve.Status().Code == codes.Error {
+		t.Errorf("successful solve span has status %v", solve.Status())
+	}
+
+	create := byName[freecap.SpanCreateTask][0]
+	if create.Parent().SpanID() != solve.SpanContext().SpanID() {
+		t.Error("CreateTask span is not a child of the SolveCaptcha span")
+	}
+
+	request := byName[freecap.SpanRequest][0]
+	if request.SpanKind() != trace.SpanKindClient {
+		t.Errorf("request span kind = %v, want client", request.SpanKind())
+	}
+	requestAttrs := attrs(request)
+	if got := requestAttrs[freecap.AttrHTTPMethod].AsString(); got != "POST" {
+		t.Errorf("%s = %q, want POST", freecap.AttrHTTPMethod, got)
+	}
+	if got := requestAttrs[freecap.AttrHTTPStatusCode].AsInt64(); got != 200 {
+		t.Errorf("%s = %d, want 200", freecap.AttrHTTPStatusCode, got)
+	}
+	if got := requestAttrs[freecap.AttrAttempt].AsInt64(); got != 1 {
+		t.Errorf("%s = %d, want 1", freecap.AttrAttempt, got)
+	}
+}
+
+func TestTracerRecordsErrors(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.SetTaskError("unsolvable")
+	client, recorder := newTracedClient(t, server)
+
+	if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), freecap.HCaptcha, 0, 0); err == nil {
+		t.Fatal("SolveCaptcha succeeded with a task error set")
+	}
+
+	for _, span := range recorder.Ended() {
+		if span.Name() != freecap.SpanSolveCaptcha {
+			continue
+		}
+		if span.Status().Code != codes.Error {
+			t.Errorf("failed solve span status = %v, want error", span.Status())
+		}
+		if len(span.Events()) == 0 || span.Events()[0].Name != "exception" {
+			t.Errorf("failed solve span has no recorded error event: %v", span.Events())
+		}
+		return
+	}
+	t.Fatal("no SolveCaptcha span recorded")
+}
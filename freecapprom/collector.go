@@ -0,0 +1,96 @@
+// Package freecapprom exposes FreeCap client metrics as Prometheus collectors.
+//
+// It is a separate module so the core client does not depend on the
+// Prometheus client library:
+//
+//	metrics := freecap.NewMetrics()
+//	config := freecap.NewClientConfig()
+//	config.Metrics = metrics
+//	prometheus.MustRegister(freecapprom.NewCollector(metrics))
+package freecapprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	freecap "github.com/freecap-su/Wrappers"
+)
+
+// Collector implements prometheus.Collector for a freecap.Metrics
+type Collector struct {
+	metrics *freecap.Metrics
+
+	tasksCreated  *prometheus.Desc
+	tasksTotal    *prometheus.Desc
+	solveDuration *prometheus.Desc
+	retriesTotal  *prometheus.Desc
+}
+
+// NewCollector creates a collector reading from the given metrics
+func NewCollector(metrics *freecap.Metrics) *Collector {
+	return &Collector{
+		metrics: metrics,
+		tasksCreated: prometheus.NewDesc(
+			"freecap_tasks_created_total",
+			"Number of FreeCap tasks created by captcha type.",
+			[]string{"type"}, nil,
+		),
+		tasksTotal: prometheus.NewDesc(
+			"freecap_tasks_total",
+			"Number of finished FreeCap tasks by captcha type and outcome.",
+			[]string{"type", "status"}, nil,
+		),
+		solveDuration: prometheus.NewDesc(
+			"freecap_solve_duration_seconds",
+			"Time from task creation to solution.",
+			[]string{"type"}, nil,
+		),
+		retriesTotal: prometheus.NewDesc(
+			"freecap_retries_total",
+			"Number of HTTP request retries.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tasksCreated
+	ch <- c.tasksTotal
+	ch <- c.solveDuration
+	ch <- c.retriesTotal
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.metrics.Snapshot()
+
+	for captchaType, tm := range snapshot.ByType {
+		label := string(captchaType)
+		if label == "" {
+			label = "unknown"
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.tasksCreated, prometheus.CounterValue, float64(tm.Created), label)
+
+		// Only terminal outcomes, so summing over status counts each task once
+		counts := []struct {
+			status string
+			value  int64
+		}{
+			{"solved", tm.Solved},
+			{"failed", tm.Failed},
+			{"timed_out", tm.TimedOut},
+		}
+		for _, count := range counts {
+			ch <- prometheus.MustNewConstMetric(c.tasksTotal, prometheus.CounterValue, float64(count.value), label, count.status)
+		}
+
+		buckets := make(map[float64]uint64, len(tm.LatencyBounds))
+		for i, bound := range tm.LatencyBounds {
+			buckets[bound.Seconds()] = uint64(tm.LatencyBuckets[i])
+		}
+		ch <- prometheus.MustNewConstHistogram(c.solveDuration, uint64(tm.Solved), tm.LatencySum.Seconds(), buckets, label)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.retriesTotal, prometheus.CounterValue, float64(snapshot.Retries))
+}
@@ -0,0 +1,87 @@
+package freecapprom
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+
+	freecap "github.com/freecap-su/Wrappers"
+	"github.com/freecap-su/Wrappers/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.InjectFailure("/CreateTask", 503, `{"status":false,"error":"busy"}`)
+
+	metrics := freecap.NewMetrics()
+	config := freecap.NewClientConfig()
+	config.RetryDelay = time.Millisecond
+	config.DefaultCheckInterval = time.Millisecond
+	config.Metrics = metrics
+	client, err := server.NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	task := &freecap.CaptchaTask{
+		Sitekey: "a9b5fb07-92ff-493f-86fe-352a2803b3df",
+		Siteurl: "discord.com",
+		Proxy:   "http://proxy.example.com:8080",
+	}
+	if _, err := client.SolveCaptcha(context.Background(), task, freecap.HCaptcha, 0, 0); err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+	server.SetTaskError("unsolvable")
+	if _, err := client.SolveCaptcha(context.Background(), task, freecap.HCaptcha, 0, 0); err == nil {
+		t.Fatal("SolveCaptcha succeeded with a task error set")
+	}
+
+	collector := NewCollector(metrics)
+	expected := `
+# HELP freecap_retries_total Number of HTTP request retries.
+# TYPE freecap_retries_total counter
+freecap_retries_total 1
+# HELP freecap_tasks_created_total Number of FreeCap tasks created by captcha type.
+# TYPE freecap_tasks_created_total counter
+freecap_tasks_created_total{type="hcaptcha"} 2
+# HELP freecap_tasks_total Number of finished FreeCap tasks by captcha type and outcome.
+# TYPE freecap_tasks_total counter
+freecap_tasks_total{status="failed",type="hcaptcha"} 1
+freecap_tasks_total{status="solved",type="hcaptcha"} 1
+freecap_tasks_total{status="timed_out",type="hcaptcha"} 0
+`
+	if err := promtestutil.CollectAndCompare(collector, strings.NewReader(expected), "freecap_tasks_created_total", "freecap_tasks_total", "freecap_retries_total"); err != nil {
+		t.Error(err)
+	}
+	if err := promtestutil.CollectAndCompare(collector, strings.NewReader(""), "freecap_unknown_total"); err != nil {
+		t.Error(err)
+	}
+
+	// The histogram has one observation and a bucket per latency bound
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "freecap_solve_duration_seconds" {
+			continue
+		}
+		histogram := family.GetMetric()[0].GetHistogram()
+		if histogram.GetSampleCount() != 1 {
+			t.Errorf("histogram count = %d, want 1", histogram.GetSampleCount())
+		}
+		if got, want := len(histogram.GetBucket()), len(freecap.MetricsLatencyBuckets); got != want {
+			t.Errorf("histogram has %d buckets, want %d", got, want)
+		}
+		return
+	}
+	t.Error("freecap_solve_duration_seconds not collected")
+}
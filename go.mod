@@ -0,0 +1,3 @@
+module github.com/freecap-su/Wrappers
+
+go 1.21
@@ -0,0 +1,69 @@
+package freecap
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+)
+
+// protectedHeaders are controlled by the client and cannot be
+// replaced by DefaultHeaders or WithHeaders
+var protectedHeaders = map[string]bool{
+	"Freecap-Key":      true,
+	"Content-Type":     true,
+	"Content-Encoding": true,
+	"Idempotency-Key":  true,
+}
+
+type headersKey struct{}
+
+// WithHeaders returns a context carrying extra headers for requests made with
+// it. They are applied after ClientConfig.DefaultHeaders, overriding them.
+// Headers already present on ctx are kept unless replaced.
+func WithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string)
+	if existing, ok := ctx.Value(headersKey{}).(map[string]string); ok {
+		for name, value := range existing {
+			merged[name] = value
+		}
+	}
+	for name, value := range headers {
+		merged[name] = value
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// applyHeaders sets the configured default headers and any per-call headers
+// from ctx on req, skipping protected ones
+func (c *FreeCapClient) applyHeaders(ctx context.Context, req *http.Request) {
+	set := func(headers map[string]string) {
+		for name, value := range headers {
+			if protectedHeaders[http.CanonicalHeaderKey(name)] {
+				continue
+			}
+			req.Header.Set(name, value)
+		}
+	}
+
+	set(c.config.DefaultHeaders)
+	if headers, ok := ctx.Value(headersKey{}).(map[string]string); ok {
+		set(headers)
+	}
+}
+
+// nextUserAgent returns the User-Agent for a request, rotating through
+// UserAgents when it is set
+func (c *FreeCapClient) nextUserAgent() string {
+	agents := c.config.UserAgents
+	switch len(agents) {
+	case 0:
+		return c.config.UserAgent
+	case 1:
+		return agents[0]
+	}
+
+	if c.config.RandomUserAgent {
+		return agents[rand.Intn(len(agents))]
+	}
+	return agents[(c.userAgentSeq.Add(1)-1)%uint64(len(agents))]
+}
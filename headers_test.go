@@ -0,0 +1,121 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCustomHeaders(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", respond(200, map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.DefaultHeaders = map[string]string{
+			"X-Team":       "payments",
+			"X-Env":        "staging",
+			"freecap-key":  "stolen-key",
+			"Content-Type": "text/plain",
+		}
+	})
+
+	ctx := WithHeaders(context.Background(), map[string]string{"X-Env": "prod"})
+	ctx = WithHeaders(ctx, map[string]string{"X-Call": "balance", "Idempotency-Key": "fixed"})
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+
+	header := api.requestsTo("/GetBalance")[0].Header
+	want := map[string]string{
+		"X-Team":       "payments",
+		"X-Env":        "prod",
+		"X-Call":       "balance",
+		"Freecap-Key":  "test-api-key",
+		"Content-Type": "application/json",
+	}
+	for name, value := range want {
+		if got := header.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if got := header.Get("Idempotency-Key"); got == "fixed" {
+		t.Error("a per-call header replaced the protected Idempotency-Key")
+	}
+}
+
+func TestUserAgentsCycle(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", respond(200, map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.UserAgents = []string{"agent-a", "agent-b", "agent-c"}
+	})
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetBalance(context.Background()); err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+	}
+	var agents []string
+	for _, req := range api.requestsTo("/GetBalance") {
+		agents = append(agents, req.Header.Get("User-Agent"))
+	}
+	if want := "[agent-a agent-b agent-c agent-a agent-b]"; fmt.Sprint(agents) != want {
+		t.Errorf("User-Agents = %v, want %s", agents, want)
+	}
+}
+
+func TestRandomUserAgent(t *testing.T) {
+	pool := []string{"agent-a", "agent-b", "agent-c"}
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", respond(200, map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.UserAgents = pool
+		config.RandomUserAgent = true
+	})
+
+	for i := 0; i < 50; i++ {
+		if _, err := client.GetBalance(context.Background()); err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, req := range api.requestsTo("/GetBalance") {
+		agent := req.Header.Get("User-Agent")
+		if agent != pool[0] && agent != pool[1] && agent != pool[2] {
+			t.Fatalf("User-Agent = %q, want one from the pool", agent)
+		}
+		seen[agent] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("50 requests used only %v, want random picks from the pool", seen)
+	}
+}
+
+func TestUserAgentStableAcrossRetries(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", failOnce(map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.UserAgents = []string{"agent-a", "agent-b"}
+	})
+
+	if _, err := client.GetBalance(context.Background()); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	requests := api.requestsTo("/GetBalance")
+	if len(requests) != 2 {
+		t.Fatalf("/GetBalance calls = %d, want a failed attempt and its retry", len(requests))
+	}
+	for i, req := range requests {
+		if agent := req.Header.Get("User-Agent"); agent != "agent-a" {
+			t.Errorf("attempt %d User-Agent = %q, want agent-a for every attempt", i+1, agent)
+		}
+	}
+}
+
+func TestUserAgentsRejectBlank(t *testing.T) {
+	config := NewClientConfig()
+	config.UserAgents = []string{"agent-a", " "}
+	if _, err := NewFreeCapClient("test-api-key", config, nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewFreeCapClient(blank user agent) = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package freecap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLogger writes one JSON object per log message with "time", "level" and
+// "msg" fields plus any fields attached with With. It is safe for concurrent
+// use; loggers derived with With share the writer and its lock.
+type JSONLogger struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	fields []jsonField
+}
+
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// NewJSONLogger creates a JSONLogger writing to w
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{mu: &sync.Mutex{}, w: w}
+}
+
+// With returns a JSONLogger that adds the given key/value pairs to every line.
+// A key without a value is recorded with a null value.
+func (j *JSONLogger) With(keysAndValues ...interface{}) *JSONLogger {
+	fields := append([]jsonField(nil), j.fields...)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		field := jsonField{key: fmt.Sprint(keysAndValues[i])}
+		if i+1 < len(keysAndValues) {
+			field.value = keysAndValues[i+1]
+		}
+		fields = append(fields, field)
+	}
+	return &JSONLogger{mu: j.mu, w: j.w, fields: fields}
+}
+
+func (j *JSONLogger) log(level LogLevel, message string, args ...interface{}) {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	entry := make(map[string]interface{}, len(j.fields)+3)
+	for _, field := range j.fields {
+		entry[field.key] = field.value
+	}
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = message
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]interface{}{
+			"time":  entry["time"],
+			"level": entry["level"],
+			"msg":   message,
+			"error": fmt.Sprintf("failed to encode log fields: %v", err),
+		})
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, _ = j.w.Write(line)
+}
+
+func (j *JSONLogger) Debug(message string, args ...interface{}) {
+	j.log(LevelDebug, message, args...)
+}
+
+func (j *JSONLogger) Info(message string, args ...interface{}) {
+	j.log(LevelInfo, message, args...)
+}
+
+func (j *JSONLogger) Warning(message string, args ...interface{}) {
+	j.log(LevelWarning, message, args...)
+}
+
+func (j *JSONLogger) Error(message string, args ...interface{}) {
+	j.log(LevelError, message, args...)
+}
@@ -0,0 +1,52 @@
+package freecap
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLoggerLine(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf).With("component", "solver", "attempt", 2)
+
+	logger.Warning("task %s failed", "task-1")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("lines = %q, want one", lines)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line %q is not JSON: %v", lines[0], err)
+	}
+	if entry["level"] != "WARNING" {
+		t.Errorf("level = %v, want WARNING", entry["level"])
+	}
+	if entry["msg"] != "task task-1 failed" {
+		t.Errorf("msg = %v, want %q", entry["msg"], "task task-1 failed")
+	}
+	if entry["component"] != "solver" || entry["attempt"] != float64(2) {
+		t.Errorf("fields = %v, want component and attempt", entry)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, entry["time"].(string)); err != nil {
+		t.Errorf("time %v is not RFC 3339: %v", entry["time"], err)
+	}
+}
+
+func TestJSONLoggerUnencodableField(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf).With("callback", func() {})
+
+	logger.Error("boom")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("line %q is not JSON: %v", buf.String(), err)
+	}
+	if entry["level"] != "ERROR" || entry["msg"] != "boom" || entry["error"] == nil {
+		t.Errorf("entry = %v, want level, msg and an encoding error", entry)
+	}
+}
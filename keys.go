@@ -0,0 +1,99 @@
+package freecap
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultKeyCooldown is how long an exhausted key is skipped when
+// KeyCooldown is not set
+const defaultKeyCooldown = 60 * time.Second
+
+// KeyProvider supplies API keys for requests and lets the client rotate away
+// from keys that are rejected or rate limited. Implementations must be safe
+// for concurrent use.
+type KeyProvider interface {
+	// Key returns the key to use for the next request, or false when every
+	// key is currently exhausted
+	Key() (string, bool)
+	// MarkExhausted reports that key was rejected (401) or rate limited (429)
+	MarkExhausted(key string)
+}
+
+// KeyRing is a KeyProvider that sticks to one key until it is exhausted, then
+// fails over to the next, skipping exhausted keys until their cooldown ends
+type KeyRing struct {
+	mu        sync.Mutex
+	keys      []string
+	current   int
+	cooldown  time.Duration
+	exhausted map[string]time.Time
+}
+
+// NewKeyRing creates a KeyRing from the given keys (duplicates and blanks are
+// dropped). A zero cooldown uses 60 seconds.
+func NewKeyRing(keys []string, cooldown time.Duration) (*KeyRing, error) {
+	if cooldown <= 0 {
+		cooldown = defaultKeyCooldown
+	}
+
+	ring := &KeyRing{
+		cooldown:  cooldown,
+		exhausted: make(map[string]time.Time),
+	}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		ring.keys = append(ring.keys, key)
+	}
+
+	if len(ring.keys) == 0 {
+		return nil, NewFreeCapValidationError("Key ring needs at least one API key")
+	}
+	return ring, nil
+}
+
+// Key implements KeyProvider
+func (r *KeyRing) Key() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(r.keys); i++ {
+		idx := (r.current + i) % len(r.keys)
+		key := r.keys[idx]
+		if until, ok := r.exhausted[key]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(r.exhausted, key)
+		}
+		r.current = idx
+		return key, true
+	}
+	return "", false
+}
+
+// MarkExhausted implements KeyProvider
+func (r *KeyRing) MarkExhausted(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exhausted[key] = time.Now().Add(r.cooldown)
+	if r.keys[r.current] == key {
+		r.current = (r.current + 1) % len(r.keys)
+	}
+}
+
+// maskKey shortens an API key for logging, keeping only its ends
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return redactedValue
+	}
+	return key[:4] + "..." + key[len(key)-4:]
+}
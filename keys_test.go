@@ -0,0 +1,123 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyFailover(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if r.Header.Get("FreeCap-Key") == "test-api-key" {
+			writeTestJSON(w, 429, map[string]interface{}{"status": false, "error": "rate limited"})
+			return
+		}
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "balance": 5.0})
+	})
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.APIKeys = []string{"second-key"}
+	})
+
+	for i := 0; i < 2; i++ {
+		balance, err := client.GetBalance(context.Background())
+		if err != nil {
+			t.Fatalf("GetBalance %d: %v", i+1, err)
+		}
+		if balance != 5.0 {
+			t.Errorf("balance = %v, want 5", balance)
+		}
+	}
+
+	var keys []string
+	for _, req := range api.requestsTo("/GetBalance") {
+		keys = append(keys, req.Header.Get("FreeCap-Key"))
+	}
+	want := []string{"test-api-key", "second-key", "second-key"}
+	if len(keys) != len(want) {
+		t.Fatalf("keys sent = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("request %d used %q, want %q (all: %v)", i+1, keys[i], want[i], keys)
+		}
+	}
+}
+
+func TestKeyRingCooldown(t *testing.T) {
+	ring, err := NewKeyRing([]string{"a", " b ", "a", ""}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+
+	ring.MarkExhausted("a")
+	if key, _ := ring.Key(); key != "b" {
+		t.Errorf("Key = %q with a exhausted, want b", key)
+	}
+	ring.MarkExhausted("b")
+	if key, ok := ring.Key(); ok {
+		t.Errorf("Key = %q with every key exhausted, want none", key)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := ring.Key(); !ok {
+		t.Error("no key available after the cooldown")
+	}
+}
+
+func TestSetAPIKeyConcurrent(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", respond(200, map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, nil)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := client.GetBalance(ctx); err != nil {
+					t.Errorf("GetBalance: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := client.SetAPIKey(fmt.Sprintf("rotated-key-%d", i)); err != nil {
+			t.Fatalf("SetAPIKey: %v", err)
+		}
+	}
+	wg.Wait()
+
+	before := len(api.requestsTo("/GetBalance"))
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetBalance(ctx); err != nil {
+			t.Fatalf("GetBalance after the swap: %v", err)
+		}
+	}
+	requests := api.requestsTo("/GetBalance")
+	for _, req := range requests[before:] {
+		if key := req.Header.Get("FreeCap-Key"); key != "rotated-key-9" {
+			t.Errorf("FreeCap-Key = %q, want the last key set", key)
+		}
+	}
+	for _, req := range requests[:before] {
+		if key := req.Header.Get("FreeCap-Key"); key != "test-api-key" && !strings.HasPrefix(key, "rotated-key-") {
+			t.Errorf("FreeCap-Key = %q during the swap, want the old or a new key", key)
+		}
+	}
+}
+
+func TestSetAPIKeyRejectsEmpty(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, nil)
+	if err := client.SetAPIKey("  "); !errors.Is(err, ErrValidation) {
+		t.Errorf("SetAPIKey(blank) = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,263 @@
+package freecap
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// metricsLatencySamples is how many recent solve latencies are kept per
+// captcha type for percentile calculation
+const metricsLatencySamples = 1024
+
+// MetricsLatencyBuckets are the upper bounds of the solve latency histogram.
+// NewMetrics copies them, so changes only affect collectors created later.
+var MetricsLatencyBuckets = []time.Duration{
+	1 * time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	20 * time.Second,
+	30 * time.Second,
+	60 * time.Second,
+	120 * time.Second,
+	300 * time.Second,
+}
+
+// Metrics collects solver counters and latencies per captcha type. It is safe
+// for concurrent use; attach it to a client with ClientConfig.Metrics.
+type Metrics struct {
+	mu      sync.Mutex
+	bounds  []time.Duration
+	byType  map[CaptchaType]*typeMetrics
+	untyped *typeMetrics
+	retries int64
+}
+
+type typeMetrics struct {
+	created  int64
+	solved   int64
+	failed   int64
+	timedOut int64
+
+	latencySum time.Duration
+	buckets    []int64
+	samples    []time.Duration
+	next       int
+}
+
+// TypeMetrics is a point-in-time view of the metrics for one captcha type
+type TypeMetrics struct {
+	Created  int64
+	Solved   int64
+	Failed   int64
+	TimedOut int64
+
+	// LatencySum is the total solve time of all solved tasks
+	LatencySum time.Duration
+	// LatencyBuckets holds cumulative counts of solves at or below the
+	// bound at the same index of LatencyBounds, the MetricsLatencyBuckets
+	// in effect when the collector was created
+	LatencyBuckets []int64
+	LatencyBounds  []time.Duration
+
+	// Percentiles over the most recent solves
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// MetricsSnapshot is a point-in-time view of all collected metrics
+type MetricsSnapshot struct {
+	Retries int64
+	ByType  map[CaptchaType]TypeMetrics
+	// Totals sums all types, plus outcomes of tasks whose captcha type is
+	// unknown, e.g. resumed with WaitForResult without a TaskStore entry
+	Totals TypeMetrics
+}
+
+// NewMetrics creates an empty metrics collector
+func NewMetrics() *Metrics {
+	bounds := append([]time.Duration(nil), MetricsLatencyBuckets...)
+	return &Metrics{
+		bounds:  bounds,
+		byType:  make(map[CaptchaType]*typeMetrics),
+		untyped: &typeMetrics{buckets: make([]int64, len(bounds))},
+	}
+}
+
+// forType returns the counters for captchaType; an empty type is only
+// counted in the totals
+func (m *Metrics) forType(captchaType CaptchaType) *typeMetrics {
+	if captchaType == "" {
+		return m.untyped
+	}
+	tm, ok := m.byType[captchaType]
+	if !ok {
+		tm = &typeMetrics{buckets: make([]int64, len(m.bounds))}
+		m.byType[captchaType] = tm
+	}
+	return tm
+}
+
+func (m *Metrics) recordCreated(captchaType CaptchaType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forType(captchaType).created++
+}
+
+func (m *Metrics) recordSolved(captchaType CaptchaType, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tm := m.forType(captchaType)
+	tm.solved++
+	tm.latencySum += latency
+	for i, bound := range m.bounds {
+		if latency <= bound {
+			tm.buckets[i]++
+		}
+	}
+
+	if len(tm.samples) < metricsLatencySamples {
+		tm.samples = append(tm.samples, latency)
+	} else {
+		tm.samples[tm.next] = latency
+		tm.next = (tm.next + 1) % metricsLatencySamples
+	}
+}
+
+func (m *Metrics) recordFailed(captchaType CaptchaType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forType(captchaType).failed++
+}
+
+func (m *Metrics) recordTimedOut(captchaType CaptchaType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forType(captchaType).timedOut++
+}
+
+func (m *Metrics) recordRetry() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+// Snapshot returns the current metric values
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Retries: m.retries,
+		ByType:  make(map[CaptchaType]TypeMetrics, len(m.byType)),
+	}
+
+	total := &typeMetrics{buckets: make([]int64, len(m.bounds))}
+	total.add(m.untyped)
+	for captchaType, tm := range m.byType {
+		snapshot.ByType[captchaType] = m.view(tm)
+		total.add(tm)
+	}
+	snapshot.Totals = m.view(total)
+
+	return snapshot
+}
+
+// view copies tm into a TypeMetrics with percentiles over its samples
+func (m *Metrics) view(tm *typeMetrics) TypeMetrics {
+	sorted := append([]time.Duration(nil), tm.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return TypeMetrics{
+		Created:        tm.created,
+		Solved:         tm.solved,
+		Failed:         tm.failed,
+		TimedOut:       tm.timedOut,
+		LatencySum:     tm.latencySum,
+		LatencyBuckets: append([]int64(nil), tm.buckets...),
+		LatencyBounds:  append([]time.Duration(nil), m.bounds...),
+		P50:            percentile(sorted, 0.50),
+		P90:            percentile(sorted, 0.90),
+		P99:            percentile(sorted, 0.99),
+	}
+}
+
+// add accumulates other's counters and samples into tm
+func (tm *typeMetrics) add(other *typeMetrics) {
+	tm.created += other.created
+	tm.solved += other.solved
+	tm.failed += other.failed
+	tm.timedOut += other.timedOut
+	tm.latencySum += other.latencySum
+	for i, n := range other.buckets {
+		tm.buckets[i] += n
+	}
+	tm.samples = append(tm.samples, other.samples...)
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// reset clears all counters and latency samples
+func (m *Metrics) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.byType = make(map[CaptchaType]*typeMetrics)
+	m.untyped = &typeMetrics{buckets: make([]int64, len(m.bounds))}
+	m.retries = 0
+}
+
+// TypeStats summarizes the solves of one captcha type since the client was
+// created or its stats were last reset
+type TypeStats struct {
+	Created    int64
+	Solved     int64
+	Failed     int64
+	TimedOut   int64
+	AvgLatency time.Duration
+}
+
+// StatsByType returns per-type solve stats for this client. Unlike a
+// configured Metrics collector, these stats can be cleared with ResetStats.
+func (c *FreeCapClient) StatsByType() map[CaptchaType]TypeStats {
+	snapshot := c.stats.Snapshot()
+
+	stats := make(map[CaptchaType]TypeStats, len(snapshot.ByType))
+	for captchaType, tm := range snapshot.ByType {
+		ts := TypeStats{
+			Created:  tm.Created,
+			Solved:   tm.Solved,
+			Failed:   tm.Failed,
+			TimedOut: tm.TimedOut,
+		}
+		if tm.Solved > 0 {
+			ts.AvgLatency = tm.LatencySum / time.Duration(tm.Solved)
+		}
+		stats[captchaType] = ts
+	}
+	return stats
+}
+
+// ResetStats clears the stats returned by StatsByType, e.g. at the start of
+// a reporting interval. Solves still running when it is called are counted
+// in the new interval when they finish. A configured Metrics collector is
+// not affected.
+func (c *FreeCapClient) ResetStats() {
+	c.stats.reset()
+}
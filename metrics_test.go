@@ -0,0 +1,197 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMetricsSnapshot(t *testing.T) {
+	m := NewMetrics()
+	for i := 1; i <= 5; i++ {
+		m.recordCreated(HCaptcha)
+		m.recordSolved(HCaptcha, time.Duration(i)*time.Second)
+	}
+	m.recordCreated(HCaptcha)
+	m.recordFailed(HCaptcha)
+	m.recordCreated(Turnstile)
+	m.recordTimedOut(Turnstile)
+	m.recordRetry()
+	m.recordRetry()
+
+	snapshot := m.Snapshot()
+	if snapshot.Retries != 2 {
+		t.Errorf("Retries = %d, want 2", snapshot.Retries)
+	}
+
+	h := snapshot.ByType[HCaptcha]
+	if h.Created != 6 || h.Solved != 5 || h.Failed != 1 || h.TimedOut != 0 {
+		t.Errorf("hCaptcha counts = %+v, want 6 created, 5 solved, 1 failed", h)
+	}
+	if h.LatencySum != 15*time.Second {
+		t.Errorf("LatencySum = %v, want 15s", h.LatencySum)
+	}
+	if h.P50 != 3*time.Second {
+		t.Errorf("P50 = %v, want 3s", h.P50)
+	}
+	if h.P99 != 5*time.Second {
+		t.Errorf("P99 = %v, want 5s", h.P99)
+	}
+
+	// Cumulative buckets: 1s, 2s, 5s and above hold 1, 2 and 5 solves
+	wantBuckets := map[time.Duration]int64{time.Second: 1, 2 * time.Second: 2, 5 * time.Second: 5, 300 * time.Second: 5}
+	for i, bound := range MetricsLatencyBuckets {
+		if want, ok := wantBuckets[bound]; ok && h.LatencyBuckets[i] != want {
+			t.Errorf("bucket <= %v = %d, want %d", bound, h.LatencyBuckets[i], want)
+		}
+	}
+
+	ts := snapshot.ByType[Turnstile]
+	if ts.Created != 1 || ts.TimedOut != 1 || ts.P50 != 0 {
+		t.Errorf("Turnstile = %+v, want 1 created and 1 timed out, no latencies", ts)
+	}
+}
+
+func TestMetricsTotalsIncludeUntypedOutcomes(t *testing.T) {
+	m := NewMetrics()
+	m.recordCreated(HCaptcha)
+	m.recordSolved(HCaptcha, time.Second)
+	m.recordSolved("", 3*time.Second)
+	m.recordFailed("")
+
+	snapshot := m.Snapshot()
+	if _, ok := snapshot.ByType[""]; ok || len(snapshot.ByType) != 1 {
+		t.Errorf("ByType = %v, want only hCaptcha", snapshot.ByType)
+	}
+	totals := snapshot.Totals
+	if totals.Created != 1 || totals.Solved != 2 || totals.Failed != 1 || totals.LatencySum != 4*time.Second {
+		t.Errorf("Totals = %+v, want 1 created, 2 solved in 4s, 1 failed", totals)
+	}
+	if totals.P99 != 3*time.Second {
+		t.Errorf("Totals.P99 = %v, want 3s", totals.P99)
+	}
+}
+
+func TestMetricsSnapshotIsACopy(t *testing.T) {
+	m := NewMetrics()
+	m.recordSolved(HCaptcha, time.Second)
+
+	snapshot := m.Snapshot()
+	snapshot.ByType[HCaptcha].LatencyBuckets[0] = 100
+	if got := m.Snapshot().ByType[HCaptcha].LatencyBuckets[0]; got != 1 {
+		t.Errorf("changing a snapshot changed the collector: bucket = %d", got)
+	}
+}
+
+func TestClientRecordsMetrics(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", sequence(taskSolved("P1_token")))
+	metrics := NewMetrics()
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.Metrics = metrics
+	})
+
+	if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0); err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+
+	h := metrics.Snapshot().ByType[HCaptcha]
+	if h.Created != 1 || h.Solved != 1 || h.P50 <= 0 {
+		t.Errorf("metrics = %+v, want one created and solved task with a latency", h)
+	}
+}
+
+func TestWaitForResultMetricsWithoutStoredType(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", respond(200, taskSolved("P1_token")))
+	metrics := NewMetrics()
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.Metrics = metrics
+	})
+
+	if _, err := client.WaitForResult(context.Background(), "task-1", 0, 0); err != nil {
+		t.Fatalf("WaitForResult: %v", err)
+	}
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot.ByType) != 0 {
+		t.Errorf("ByType = %v, want no per-type metrics for an unknown type", snapshot.ByType)
+	}
+	if snapshot.Totals.Solved != 1 {
+		t.Errorf("Totals.Solved = %d, want 1", snapshot.Totals.Solved)
+	}
+	if stats := client.StatsByType(); len(stats) != 0 {
+		t.Errorf("StatsByType = %v, want empty", stats)
+	}
+}
+
+func TestMetricsKeepBoundsFromCreation(t *testing.T) {
+	saved := MetricsLatencyBuckets
+	defer func() { MetricsLatencyBuckets = saved }()
+
+	m := NewMetrics()
+	m.recordSolved(HCaptcha, time.Second)
+
+	// Changing the package buckets later must not affect this collector
+	MetricsLatencyBuckets = []time.Duration{time.Millisecond}
+	m.recordSolved(HCaptcha, 2*time.Second)
+
+	h := m.Snapshot().ByType[HCaptcha]
+	if len(h.LatencyBounds) != len(saved) || len(h.LatencyBuckets) != len(saved) {
+		t.Fatalf("got %d bounds and %d buckets, want %d of each", len(h.LatencyBounds), len(h.LatencyBuckets), len(saved))
+	}
+	for i, bound := range saved {
+		if h.LatencyBounds[i] != bound {
+			t.Errorf("bound %d = %v, want %v", i, h.LatencyBounds[i], bound)
+		}
+	}
+}
+
+func TestStatsByTypeAndReset(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/CreateTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		sitekey := body["payload"].(map[string]interface{})["websiteKey"]
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "taskId": fmt.Sprintf("task-%v", sitekey)})
+	})
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if body["taskId"] == "task-turnstile" {
+			writeTestJSON(w, 200, map[string]interface{}{"status": string(Failed), "error": "unsolvable"})
+			return
+		}
+		writeTestJSON(w, 200, taskSolved("P1_token"))
+	})
+	client := newTestClient(t, api.URL, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SolveCaptcha(ctx, hcaptchaTask(), HCaptcha, 0, 0); err != nil {
+			t.Fatalf("SolveCaptcha(hCaptcha): %v", err)
+		}
+	}
+	turnstile := &CaptchaTask{Sitekey: "turnstile", Siteurl: "example.com"}
+	if _, err := client.SolveCaptcha(ctx, turnstile, Turnstile, 0, 0); !errors.Is(err, ErrTaskFailed) {
+		t.Fatalf("SolveCaptcha(Turnstile) = %v, want ErrTaskFailed", err)
+	}
+
+	stats := client.StatsByType()
+	if h := stats[HCaptcha]; h.Created != 2 || h.Solved != 2 || h.Failed != 0 || h.AvgLatency <= 0 {
+		t.Errorf("hCaptcha stats = %+v, want 2 created and solved with a latency", h)
+	}
+	if ts := stats[Turnstile]; ts.Created != 1 || ts.Solved != 0 || ts.Failed != 1 || ts.AvgLatency != 0 {
+		t.Errorf("Turnstile stats = %+v, want 1 created and failed", ts)
+	}
+
+	client.ResetStats()
+	if stats := client.StatsByType(); len(stats) != 0 {
+		t.Errorf("StatsByType after ResetStats = %+v, want none", stats)
+	}
+	if _, err := client.SolveCaptcha(ctx, hcaptchaTask(), HCaptcha, 0, 0); err != nil {
+		t.Fatalf("SolveCaptcha after reset: %v", err)
+	}
+	if h := client.StatsByType()[HCaptcha]; h.Created != 1 || h.Solved != 1 {
+		t.Errorf("hCaptcha stats after reset = %+v, want only the new solve", h)
+	}
+}
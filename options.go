@@ -0,0 +1,145 @@
+package freecap
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ClientOption configures a client created by NewFreeCapClientWithOptions
+type ClientOption func(*clientOptions) error
+
+// clientOptions collects option values before the client is built
+type clientOptions struct {
+	config *ClientConfig
+	logger Logger
+}
+
+// NewFreeCapClientWithOptions creates a new FreeCap client from the default
+// configuration with the given options applied on top
+func NewFreeCapClientWithOptions(apiKey string, opts ...ClientOption) (*FreeCapClient, error) {
+	o := &clientOptions{config: NewClientConfig()}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewFreeCapClient(apiKey, o.config, o.logger)
+}
+
+// WithTimeout sets the per-request HTTP timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		if timeout <= 0 {
+			return NewFreeCapValidationError("Timeout must be positive")
+		}
+		o.config.RequestTimeout = timeout
+		return nil
+	}
+}
+
+// WithMaxRetries sets the maximum number of retries per request
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(o *clientOptions) error {
+		if maxRetries < 0 {
+			return NewFreeCapValidationError("Max retries cannot be negative")
+		}
+		o.config.MaxRetries = maxRetries
+		return nil
+	}
+}
+
+// WithRetryDelay sets the base delay between retries
+func WithRetryDelay(delay time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		if delay < 0 {
+			return NewFreeCapValidationError("Retry delay cannot be negative")
+		}
+		o.config.RetryDelay = delay
+		return nil
+	}
+}
+
+// WithAPIURL sets the API base URL
+func WithAPIURL(apiURL string) ClientOption {
+	return func(o *clientOptions) error {
+		if strings.TrimSpace(apiURL) == "" {
+			return NewFreeCapValidationError("API URL cannot be empty")
+		}
+		o.config.APIURL = strings.TrimSpace(apiURL)
+		return nil
+	}
+}
+
+// WithLogger sets the logger used by the client
+func WithLogger(logger Logger) ClientOption {
+	return func(o *clientOptions) error {
+		if logger == nil {
+			return NewFreeCapValidationError("Logger cannot be nil")
+		}
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to make requests. The client is
+// used as-is; WithTimeout and WithTransport do not modify it.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *clientOptions) error {
+		if httpClient == nil {
+			return NewFreeCapValidationError("HTTP client cannot be nil")
+		}
+		o.config.HTTPClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the transport of the internally created HTTP client
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *clientOptions) error {
+		if transport == nil {
+			return NewFreeCapValidationError("Transport cannot be nil")
+		}
+		o.config.Transport = transport
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the internally created transport
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(o *clientOptions) error {
+		if tlsConfig == nil {
+			return NewFreeCapValidationError("TLS config cannot be nil")
+		}
+		o.config.TLSConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithHTTPDoer sets the HTTPDoer used to send all requests
+func WithHTTPDoer(doer HTTPDoer) ClientOption {
+	return func(o *clientOptions) error {
+		if doer == nil {
+			return NewFreeCapValidationError("HTTP doer cannot be nil")
+		}
+		o.config.Doer = doer
+		return nil
+	}
+}
+
+// WithRegion selects the API endpoint by region; WithAPIURL takes precedence
+func WithRegion(region Region) ClientOption {
+	return func(o *clientOptions) error {
+		if region == "" {
+			return NewFreeCapValidationError("Region cannot be empty")
+		}
+		o.config.Region = region
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOptionsOverrideDefaults(t *testing.T) {
+	client, err := NewFreeCapClientWithOptions("test-api-key",
+		WithTimeout(7*time.Second),
+		WithMaxRetries(5),
+		WithRetryDelay(250*time.Millisecond),
+		WithAPIURL("  https://api.example.com  "),
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("NewFreeCapClientWithOptions: %v", err)
+	}
+	defer client.Close()
+
+	if client.config.RequestTimeout != 7*time.Second {
+		t.Errorf("RequestTimeout = %v, want 7s", client.config.RequestTimeout)
+	}
+	if client.config.MaxRetries != 5 {
+		t.Errorf("MaxRetries = %d, want 5", client.config.MaxRetries)
+	}
+	if client.config.RetryDelay != 250*time.Millisecond {
+		t.Errorf("RetryDelay = %v, want 250ms", client.config.RetryDelay)
+	}
+	if client.baseURL != "https://api.example.com" {
+		t.Errorf("baseURL = %q, want https://api.example.com", client.baseURL)
+	}
+
+	// Options that were not given keep their defaults
+	defaults := NewClientConfig()
+	if client.config.DefaultTaskTimeout != defaults.DefaultTaskTimeout {
+		t.Errorf("DefaultTaskTimeout = %v, want default %v", client.config.DefaultTaskTimeout, defaults.DefaultTaskTimeout)
+	}
+}
+
+func TestOptionsRejectInvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  ClientOption
+	}{
+		{"zero timeout", WithTimeout(0)},
+		{"negative timeout", WithTimeout(-time.Second)},
+		{"negative max retries", WithMaxRetries(-1)},
+		{"negative retry delay", WithRetryDelay(-time.Millisecond)},
+		{"empty API URL", WithAPIURL("   ")},
+		{"nil logger", WithLogger(nil)},
+		{"nil HTTP client", WithHTTPClient(nil)},
+		{"nil transport", WithTransport(nil)},
+		{"nil TLS config", WithTLSConfig(nil)},
+		{"nil HTTP doer", WithHTTPDoer(nil)},
+		{"empty region", WithRegion("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewFreeCapClientWithOptions("test-api-key", tt.opt)
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("NewFreeCapClientWithOptions = %v, want validation error", err)
+			}
+			if client != nil {
+				t.Error("client returned alongside an error")
+			}
+		})
+	}
+}
+
+// countingTransport counts the requests sent through it
+type countingTransport struct {
+	calls atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPClientKeepsTimeoutAndTransport(t *testing.T) {
+	api := newFakeAPI(t)
+	release := make(chan struct{})
+	defer close(release)
+	api.handle("/GetBalance", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	transport := &countingTransport{}
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond, Transport: transport}
+
+	client, err := NewFreeCapClientWithOptions("test-api-key",
+		WithAPIURL(api.URL),
+		WithHTTPClient(httpClient),
+		WithTimeout(time.Minute),
+		WithMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("NewFreeCapClientWithOptions: %v", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	if _, err := client.GetBalance(context.Background()); err == nil {
+		t.Fatal("GetBalance succeeded against a hung server")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetBalance took %v; the HTTP client's 50ms timeout was not used", elapsed)
+	}
+	if n := transport.calls.Load(); n != 1 {
+		t.Errorf("custom transport saw %d requests, want 1", n)
+	}
+	if httpClient.Timeout != 50*time.Millisecond || httpClient.Transport != transport {
+		t.Error("the supplied HTTP client was modified")
+	}
+}
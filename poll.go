@@ -0,0 +1,105 @@
+package freecap
+
+import "time"
+
+// Default bounds for adaptive polling
+const (
+	defaultMinCheckInterval = 1 * time.Second
+	defaultMaxCheckInterval = 10 * time.Second
+)
+
+// poller schedules task status checks. In the default fixed mode it wraps a
+// ticker. In adaptive mode the wait starts at MinCheckInterval and grows by
+// that step after every still-processing poll, up to MaxCheckInterval. With a
+// PollBackoffFactor the wait is multiplied by the factor instead.
+type poller struct {
+	ticker Ticker
+
+	clock    Clock
+	next     <-chan time.Time
+	interval time.Duration
+	step     time.Duration
+	factor   float64
+	max      time.Duration
+}
+
+// newPoller creates a poller for the client's polling mode
+func (c *FreeCapClient) newPoller(checkInterval time.Duration) *poller {
+	geometric := c.config.PollBackoffFactor > 1
+	if !c.config.AdaptivePolling && !geometric {
+		return &poller{ticker: c.clock.NewTicker(checkInterval)}
+	}
+
+	maxInterval := c.config.MaxCheckInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxCheckInterval
+	}
+
+	// Geometric polling on its own starts from the solve's check interval
+	minInterval := checkInterval
+	if c.config.AdaptivePolling {
+		minInterval = c.config.MinCheckInterval
+		if minInterval <= 0 {
+			minInterval = defaultMinCheckInterval
+		}
+	}
+	if minInterval > maxInterval {
+		minInterval = maxInterval
+	}
+
+	p := &poller{
+		clock:    c.clock,
+		interval: minInterval,
+		step:     minInterval,
+		max:      maxInterval,
+	}
+	if geometric {
+		p.factor = c.config.PollBackoffFactor
+	}
+	return p
+}
+
+// C returns the channel that fires when the next poll is due
+func (p *poller) C() <-chan time.Time {
+	if p.ticker != nil {
+		return p.ticker.C()
+	}
+
+	if p.next == nil {
+		p.next = p.clock.After(p.interval)
+	}
+	return p.next
+}
+
+// fired records that the channel returned by C has been received from
+func (p *poller) fired() {
+	p.next = nil
+}
+
+// advance lengthens the wait before the next poll after a still-processing status
+func (p *poller) advance() {
+	if p.ticker != nil {
+		return
+	}
+
+	if p.factor > 1 {
+		next := float64(p.interval) * p.factor
+		if next > float64(p.max) {
+			p.interval = p.max
+		} else {
+			p.interval = time.Duration(next)
+		}
+	} else {
+		p.interval += p.step
+	}
+	if p.interval > p.max {
+		p.interval = p.max
+	}
+}
+
+// stop releases the poller's ticker
+func (p *poller) stop() {
+	if p.ticker != nil {
+		p.ticker.Stop()
+	}
+}
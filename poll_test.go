@@ -0,0 +1,192 @@
+package freecap
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// pollIntervals returns the first n waits of a poller, advancing it after each
+func pollIntervals(p *poller, n int) []time.Duration {
+	intervals := make([]time.Duration, n)
+	for i := range intervals {
+		intervals[i] = p.interval
+		p.advance()
+	}
+	return intervals
+}
+
+func TestAdaptivePollingGrowsToCap(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.AdaptivePolling = true
+		config.MinCheckInterval = time.Second
+		config.MaxCheckInterval = 4 * time.Second
+	})
+
+	p := client.newPoller(3 * time.Second)
+	defer p.stop()
+
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second}
+	got := pollIntervals(p, len(want))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("interval %d = %v, want %v (all: %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestAdaptivePollingDefaults(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.AdaptivePolling = true
+	})
+
+	p := client.newPoller(3 * time.Second)
+	defer p.stop()
+
+	got := pollIntervals(p, 12)
+	if got[0] != defaultMinCheckInterval {
+		t.Errorf("first interval = %v, want %v", got[0], defaultMinCheckInterval)
+	}
+	if last := got[len(got)-1]; last != defaultMaxCheckInterval {
+		t.Errorf("interval after many polls = %v, want %v", last, defaultMaxCheckInterval)
+	}
+}
+
+func TestAdaptivePollingSolves(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", sequence(taskStatus(Processing), taskStatus(Processing), taskStatus(Processing), taskSolved("P1_token")))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.AdaptivePolling = true
+		config.MinCheckInterval = 10 * time.Millisecond
+		config.MaxCheckInterval = 20 * time.Millisecond
+	})
+
+	if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0); err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+
+	// Gaps grow from 10ms to the 20ms cap; the timer never fires early
+	polls := api.requestsTo("/GetTask")
+	minGaps := []time.Duration{20 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond}
+	for i := 1; i < len(polls); i++ {
+		if gap := polls[i].Time.Sub(polls[i-1].Time); gap < minGaps[i-1]-2*time.Millisecond {
+			t.Errorf("gap before poll %d = %v, want about %v", i+1, gap, minGaps[i-1])
+		}
+	}
+}
+
+func TestGeometricPollingGrowsToCap(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.PollBackoffFactor = 2
+		config.MaxCheckInterval = 5 * time.Second
+	})
+
+	p := client.newPoller(time.Second)
+	defer p.stop()
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	got := pollIntervals(p, len(want))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("interval %d = %v, want %v (all: %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestGeometricPollingWithAdaptiveStart(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.AdaptivePolling = true
+		config.PollBackoffFactor = 1.5
+		config.MinCheckInterval = 2 * time.Second
+		config.MaxCheckInterval = 6 * time.Second
+	})
+
+	p := client.newPoller(time.Second)
+	defer p.stop()
+
+	// The wait starts at MinCheckInterval, not the check interval
+	want := []time.Duration{2 * time.Second, 3 * time.Second, 4500 * time.Millisecond, 6 * time.Second, 6 * time.Second}
+	got := pollIntervals(p, len(want))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("interval %d = %v, want %v (all: %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestGeometricPollingGaps(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", sequence(taskStatus(Processing), taskStatus(Processing), taskStatus(Processing), taskStatus(Processing), taskSolved("P1_token")))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.PollBackoffFactor = 2
+		config.MaxCheckInterval = 40 * time.Millisecond
+	})
+
+	if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 10*time.Millisecond); err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+
+	// Gaps double from 10ms and stop growing at the 40ms cap
+	polls := api.requestsTo("/GetTask")
+	minGaps := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	if len(polls) != len(minGaps)+1 {
+		t.Fatalf("/GetTask calls = %d, want %d", len(polls), len(minGaps)+1)
+	}
+	for i := 1; i < len(polls); i++ {
+		if gap := polls[i].Time.Sub(polls[i-1].Time); gap < minGaps[i-1]-2*time.Millisecond {
+			t.Errorf("gap before poll %d = %v, want at least %v", i+1, gap, minGaps[i-1])
+		}
+	}
+}
+
+func TestTypeDefaultsTimings(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.TypeDefaults = map[CaptchaType]SolveDefaults{
+			FunCaptcha: {Timeout: time.Minute, CheckInterval: 40 * time.Millisecond},
+			HCaptcha:   {CheckInterval: 20 * time.Millisecond},
+		}
+	})
+	tests := []struct {
+		name                           string
+		captchaType                    CaptchaType
+		timeout, checkInterval         time.Duration
+		wantTimeout, wantCheckInterval time.Duration
+	}{
+		{"type defaults", FunCaptcha, 0, 0, time.Minute, 40 * time.Millisecond},
+		{"explicit values win", FunCaptcha, time.Hour, time.Second, time.Hour, time.Second},
+		{"zero field falls back", HCaptcha, 0, 0, 5 * time.Second, 20 * time.Millisecond},
+		{"no type defaults", Turnstile, 0, 0, 5 * time.Second, 5 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		timeout, checkInterval, err := client.solveTimings(tt.captchaType, tt.timeout, tt.checkInterval)
+		if err != nil || timeout != tt.wantTimeout || checkInterval != tt.wantCheckInterval {
+			t.Errorf("%s: solveTimings = %v, %v, %v; want %v, %v", tt.name, timeout, checkInterval, err, tt.wantTimeout, tt.wantCheckInterval)
+		}
+	}
+}
+
+func TestFunCaptchaUsesTypeDefaultInterval(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", sequence(taskStatus(Processing), taskStatus(Processing), taskSolved("token")))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.TypeDefaults = map[CaptchaType]SolveDefaults{
+			FunCaptcha: {CheckInterval: 40 * time.Millisecond},
+		}
+	})
+
+	task := &CaptchaTask{Preset: RobloxLogin, Proxy: "http://proxy.example.com:8080"}
+	if _, err := client.SolveCaptcha(context.Background(), task, FunCaptcha, 0, 0); err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+
+	// Polls are spaced by the 40ms FunCaptcha interval, not the 5ms global one
+	polls := api.requestsTo("/GetTask")
+	if len(polls) != 3 {
+		t.Fatalf("/GetTask calls = %d, want 3", len(polls))
+	}
+	for i := 1; i < len(polls); i++ {
+		if gap := polls[i].Time.Sub(polls[i-1].Time); gap < 38*time.Millisecond {
+			t.Errorf("gap before poll %d = %v, want at least 40ms", i+1, gap)
+		}
+	}
+}
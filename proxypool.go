@@ -0,0 +1,131 @@
+package freecap
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default ProxyPool settings used when NewProxyPool gets zero values
+const (
+	defaultProxyMaxFailures = 3
+	defaultProxyCooldown    = 5 * time.Minute
+)
+
+// ProxyPool rotates through a list of proxies round-robin, benching proxies
+// that fail repeatedly until a cooldown elapses. It is safe for concurrent use.
+type ProxyPool struct {
+	mu          sync.Mutex
+	proxies     []*pooledProxy
+	next        int
+	maxFailures int
+	cooldown    time.Duration
+}
+
+type pooledProxy struct {
+	proxy        string
+	failures     int
+	benchedUntil time.Time
+}
+
+// NewProxyPool creates a pool from the given proxy strings. A proxy is benched
+// for cooldown after maxFailures consecutive MarkBad calls. Zero values use
+// defaults of 3 failures and 5 minutes.
+func NewProxyPool(proxies []string, maxFailures int, cooldown time.Duration) (*ProxyPool, error) {
+	if len(proxies) == 0 {
+		return nil, NewFreeCapValidationError("proxy pool cannot be empty")
+	}
+	if maxFailures <= 0 {
+		maxFailures = defaultProxyMaxFailures
+	}
+	if cooldown <= 0 {
+		cooldown = defaultProxyCooldown
+	}
+
+	pool := &ProxyPool{
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+	}
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if _, err := ParseProxy(proxy); err != nil {
+			return nil, err
+		}
+		pool.proxies = append(pool.proxies, &pooledProxy{proxy: proxy})
+	}
+
+	return pool, nil
+}
+
+// Next returns the next available proxy in round-robin order. It returns
+// false when every proxy is currently benched.
+func (p *ProxyPool) Next() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(p.proxies); i++ {
+		candidate := p.proxies[(p.next+i)%len(p.proxies)]
+		if now.Before(candidate.benchedUntil) {
+			continue
+		}
+		if !candidate.benchedUntil.IsZero() {
+			// Cooldown elapsed, give the proxy a fresh start
+			candidate.benchedUntil = time.Time{}
+			candidate.failures = 0
+		}
+		p.next = (p.next + i + 1) % len(p.proxies)
+		return candidate.proxy, true
+	}
+
+	return "", false
+}
+
+// MarkBad records a failure for proxy, benching it once it reaches the
+// failure limit
+func (p *ProxyPool) MarkBad(proxy string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry := p.find(proxy); entry != nil {
+		entry.failures++
+		if entry.failures >= p.maxFailures {
+			entry.benchedUntil = time.Now().Add(p.cooldown)
+		}
+	}
+}
+
+// MarkGood resets the failure count for proxy
+func (p *ProxyPool) MarkGood(proxy string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry := p.find(proxy); entry != nil {
+		entry.failures = 0
+	}
+}
+
+// Available returns the number of proxies not currently benched
+func (p *ProxyPool) Available() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for _, entry := range p.proxies {
+		if !now.Before(entry.benchedUntil) {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *ProxyPool) find(proxy string) *pooledProxy {
+	proxy = strings.TrimSpace(proxy)
+	for _, entry := range p.proxies {
+		if entry.proxy == proxy {
+			return entry
+		}
+	}
+	return nil
+}
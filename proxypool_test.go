@@ -0,0 +1,162 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProxyPoolRotates(t *testing.T) {
+	proxies := []string{"http://a.example.com:8080", "http://b.example.com:8080", "http://c.example.com:8080"}
+	pool, err := NewProxyPool(proxies, 0, 0)
+	if err != nil {
+		t.Fatalf("NewProxyPool: %v", err)
+	}
+
+	for round := 0; round < 2; round++ {
+		for _, want := range proxies {
+			got, ok := pool.Next()
+			if !ok || got != want {
+				t.Errorf("Next = %q, %v; want %q", got, ok, want)
+			}
+		}
+	}
+}
+
+func TestProxyPoolBenchesAndReinstates(t *testing.T) {
+	pool, err := NewProxyPool([]string{"http://a.example.com:8080", "http://b.example.com:8080"}, 2, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewProxyPool: %v", err)
+	}
+
+	// A success resets the failure count, so the proxy is not benched
+	pool.MarkBad("http://a.example.com:8080")
+	pool.MarkGood("http://a.example.com:8080")
+	pool.MarkBad("http://a.example.com:8080")
+	if n := pool.Available(); n != 2 {
+		t.Fatalf("Available = %d after interleaved results, want 2", n)
+	}
+
+	pool.MarkBad("http://a.example.com:8080")
+	if n := pool.Available(); n != 1 {
+		t.Fatalf("Available = %d after two failures, want 1", n)
+	}
+	for i := 0; i < 3; i++ {
+		if got, _ := pool.Next(); got != "http://b.example.com:8080" {
+			t.Errorf("Next = %q while a is benched, want b", got)
+		}
+	}
+
+	pool.MarkBad("http://b.example.com:8080")
+	pool.MarkBad("http://b.example.com:8080")
+	if got, ok := pool.Next(); ok {
+		t.Errorf("Next = %q with every proxy benched, want none", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if n := pool.Available(); n != 2 {
+		t.Errorf("Available = %d after the cooldown, want 2", n)
+	}
+	if _, ok := pool.Next(); !ok {
+		t.Error("no proxy returned after the cooldown")
+	}
+}
+
+func TestNewProxyPoolRejectsInvalid(t *testing.T) {
+	if _, err := NewProxyPool(nil, 0, 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewProxyPool(nil) = %v, want validation error", err)
+	}
+	if _, err := NewProxyPool([]string{"http://a.example.com:8080", "not a proxy"}, 0, 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewProxyPool(bad proxy) = %v, want validation error", err)
+	}
+}
+
+func TestSolveReportsPooledProxy(t *testing.T) {
+	const bad, good = "http://bad.example.com:8080", "http://good.example.com:8080"
+	pool, err := NewProxyPool([]string{bad, good}, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("NewProxyPool: %v", err)
+	}
+
+	api := newFakeAPI(t)
+	api.handle("/CreateTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		proxy := body["payload"].(map[string]interface{})["proxy"]
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "taskId": proxy})
+	})
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if body["taskId"] == bad {
+			writeTestJSON(w, 200, map[string]interface{}{"status": string(Failed), "error": "proxy connection failed"})
+			return
+		}
+		writeTestJSON(w, 200, taskSolved("P1_token"))
+	})
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.ProxyPool = pool
+	})
+
+	task := hcaptchaTask()
+	task.Proxy = ""
+
+	if _, err := client.SolveCaptcha(context.Background(), task, HCaptcha, 0, 0); !errors.Is(err, ErrTaskFailed) {
+		t.Fatalf("first solve = %v, want ErrTaskFailed through the bad proxy", err)
+	}
+	if n := pool.Available(); n != 1 {
+		t.Fatalf("Available = %d after a failed task, want the bad proxy benched", n)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.SolveCaptcha(context.Background(), task, HCaptcha, 0, 0); err != nil {
+			t.Fatalf("solve %d: %v", i+2, err)
+		}
+	}
+	for i, req := range api.requestsTo("/CreateTask")[1:] {
+		if proxy := req.Body["payload"].(map[string]interface{})["proxy"]; proxy != good {
+			t.Errorf("solve %d used %q after the bad proxy was benched, want %q", i+2, proxy, good)
+		}
+	}
+
+	// A task-supplied proxy is not the pool's to judge
+	task.Proxy = bad
+	_, _ = client.SolveCaptcha(context.Background(), task, HCaptcha, 0, 0)
+	if n := pool.Available(); n != 1 {
+		t.Errorf("Available = %d, want 1; a task's own proxy changed the pool", n)
+	}
+}
+
+func TestInvalidTaskDoesNotRotateProxyPool(t *testing.T) {
+	const first, second = "http://a.example.com:8080", "http://b.example.com:8080"
+	pool, err := NewProxyPool([]string{first, second}, 0, 0)
+	if err != nil {
+		t.Fatalf("NewProxyPool: %v", err)
+	}
+	api := newFakeAPI(t)
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.ProxyPool = pool
+		config.MissingProxyPolicy = ProxyPolicyRequire
+	})
+
+	invalid := hcaptchaTask()
+	invalid.Proxy = ""
+	invalid.Sitekey = ""
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateTask(context.Background(), invalid, HCaptcha); !errors.Is(err, ErrValidation) {
+			t.Fatalf("CreateTask(invalid) = %v, want validation error", err)
+		}
+	}
+
+	// The pool satisfies ProxyPolicyRequire and still hands out its first proxy
+	task := hcaptchaTask()
+	task.Proxy = ""
+	if _, err := client.CreateTask(context.Background(), task, HCaptcha); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	requests := api.requestsTo("/CreateTask")
+	if len(requests) != 1 {
+		t.Fatalf("got %d CreateTask requests, want 1", len(requests))
+	}
+	if proxy := requests[0].Body["payload"].(map[string]interface{})["proxy"]; proxy != first {
+		t.Errorf("proxy = %v, want %q; invalid tasks advanced the rotation", proxy, first)
+	}
+}
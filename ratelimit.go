@@ -0,0 +1,173 @@
+package freecap
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting outgoing requests per second
+type rateLimiter struct {
+	mu     sync.Mutex
+	clock  Clock
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a token bucket allowing rate requests per second
+// with bursts of up to burst requests (at least 1)
+func newRateLimiter(clock Clock, rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		clock:  clock,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.clock.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if err := sleepClock(ctx, l.clock, delay); err != nil {
+		// Give back the reserved token since the request won't be made
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// keyedLimiter spaces out operations sharing the same key, keeping a
+// separate single-token bucket per key
+type keyedLimiter struct {
+	mu       sync.Mutex
+	clock    Clock
+	rate     float64
+	limiters map[string]*rateLimiter
+}
+
+// newKeyedLimiter creates a limiter allowing one operation per key every gap
+func newKeyedLimiter(clock Clock, gap time.Duration) *keyedLimiter {
+	return &keyedLimiter{
+		clock:    clock,
+		rate:     1 / gap.Seconds(),
+		limiters: make(map[string]*rateLimiter),
+	}
+}
+
+// Wait blocks until an operation for key may proceed or the context is done
+func (k *keyedLimiter) Wait(ctx context.Context, key string) error {
+	k.mu.Lock()
+	limiter, ok := k.limiters[key]
+	if !ok {
+		limiter = newRateLimiter(k.clock, k.rate, 1)
+		k.limiters[key] = limiter
+	}
+	k.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// RateLimitInfo is the server's rate-limit state from the latest response
+// that carried rate-limit headers. Limit and Remaining are -1 when the
+// server did not report them; Reset is zero when unknown.
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	// UpdatedAt is when the headers were received; zero if never
+	UpdatedAt time.Time
+}
+
+// serverRateLimit holds the latest RateLimitInfo seen by a client
+type serverRateLimit struct {
+	mu   sync.Mutex
+	info RateLimitInfo
+}
+
+// RateLimitStatus returns the most recent rate-limit headers reported by the
+// server, so callers can slow down before hitting 429
+func (c *FreeCapClient) RateLimitStatus() RateLimitInfo {
+	c.serverLimit.mu.Lock()
+	defer c.serverLimit.mu.Unlock()
+
+	if c.serverLimit.info.UpdatedAt.IsZero() {
+		return RateLimitInfo{Limit: -1, Remaining: -1}
+	}
+	return c.serverLimit.info
+}
+
+// updateRateLimit records rate-limit headers from a response, keeping the
+// previous snapshot when the response has none
+func (c *FreeCapClient) updateRateLimit(header http.Header) {
+	info, ok := parseRateLimitHeaders(header, c.clock.Now())
+	if !ok {
+		return
+	}
+
+	c.serverLimit.mu.Lock()
+	c.serverLimit.info = info
+	c.serverLimit.mu.Unlock()
+}
+
+// parseRateLimitHeaders reads X-RateLimit-* headers, or the unprefixed
+// RateLimit-* ones. Reset may be seconds from now, a Unix timestamp or an
+// HTTP date.
+func parseRateLimitHeaders(header http.Header, now time.Time) (RateLimitInfo, bool) {
+	get := func(name string) string {
+		if value := strings.TrimSpace(header.Get("X-" + name)); value != "" {
+			return value
+		}
+		return strings.TrimSpace(header.Get(name))
+	}
+
+	info := RateLimitInfo{Limit: -1, Remaining: -1, UpdatedAt: now}
+	found := false
+
+	if n, err := strconv.Atoi(get("RateLimit-Limit")); err == nil && n >= 0 {
+		info.Limit = n
+		found = true
+	}
+	if n, err := strconv.Atoi(get("RateLimit-Remaining")); err == nil && n >= 0 {
+		info.Remaining = n
+		found = true
+	}
+	if reset := get("RateLimit-Reset"); reset != "" {
+		if seconds, err := strconv.ParseInt(reset, 10, 64); err == nil && seconds >= 0 {
+			// Values this large are timestamps rather than a delay
+			if seconds > 1_000_000_000 {
+				info.Reset = time.Unix(seconds, 0)
+			} else {
+				info.Reset = now.Add(time.Duration(seconds) * time.Second)
+			}
+			found = true
+		} else if date, err := http.ParseTime(reset); err == nil {
+			info.Reset = date
+			found = true
+		}
+	}
+
+	return info, found
+}
@@ -0,0 +1,191 @@
+package freecap
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", respond(200, map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.RequestsPerSecond = 20
+		config.RateLimitBurst = 1
+	})
+
+	const calls = 5
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetBalance(context.Background()); err != nil {
+				t.Errorf("GetBalance: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	requests := api.requestsTo("/GetBalance")
+	if len(requests) != calls {
+		t.Fatalf("got %d requests, want %d", len(requests), calls)
+	}
+
+	// 1/RPS = 50ms; allow some slack for timer granularity
+	const minGap = 40 * time.Millisecond
+	for i := 1; i < len(requests); i++ {
+		if gap := requests[i].Time.Sub(requests[i-1].Time); gap < minGap {
+			t.Errorf("gap between requests %d and %d = %v, want at least %v", i-1, i, gap, minGap)
+		}
+	}
+	if total := requests[calls-1].Time.Sub(requests[0].Time); total < (calls-1)*minGap {
+		t.Errorf("%d requests took %v, want at least %v", calls, total, (calls-1)*minGap)
+	}
+}
+
+func TestRateLimiterWaitIsCancellable(t *testing.T) {
+	limiter := newRateLimiter(realClock{}, 0.1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("Wait ignored the context deadline")
+	}
+
+	// The cancelled wait hands its token back instead of pushing the
+	// next caller further out
+	limiter.mu.Lock()
+	tokens := limiter.tokens
+	limiter.mu.Unlock()
+	if tokens < -0.5 {
+		t.Errorf("tokens = %v after a cancelled wait, want the reservation returned", tokens)
+	}
+}
+
+func TestGroqKeyIntervalSpacesTasks(t *testing.T) {
+	const gap = 80 * time.Millisecond
+
+	api := newFakeAPI(t)
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.GroqKeyInterval = gap
+	})
+	task := func(groqKey string) *CaptchaTask {
+		task := hcaptchaTask()
+		task.GroqAPIKey = groqKey
+		return task
+	}
+
+	for _, groqKey := range []string{"gsk_one", "gsk_one", "gsk_two"} {
+		if _, err := client.CreateTask(context.Background(), task(groqKey), HCaptcha); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	creates := api.requestsTo("/CreateTask")
+	if len(creates) != 3 {
+		t.Fatalf("/CreateTask calls = %d, want 3", len(creates))
+	}
+	// The second task shares the first one's key and waits out the gap; the
+	// third uses another key and goes straight through
+	if spacing := creates[1].Time.Sub(creates[0].Time); spacing < gap-5*time.Millisecond {
+		t.Errorf("tasks sharing a Groq key were %v apart, want at least %v", spacing, gap)
+	}
+	if spacing := creates[2].Time.Sub(creates[1].Time); spacing >= gap/2 {
+		t.Errorf("a task with another Groq key waited %v", spacing)
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name             string
+		header           http.Header
+		limit, remaining int
+		reset            time.Time
+	}{
+		{
+			"reset in seconds",
+			http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"42"}, "X-Ratelimit-Reset": {"30"}},
+			100, 42, now.Add(30 * time.Second),
+		},
+		{
+			"reset as unix timestamp",
+			http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1772366460"}},
+			-1, 0, time.Unix(1772366460, 0),
+		},
+		{
+			"reset as HTTP date",
+			http.Header{"Ratelimit-Limit": {"10"}, "Ratelimit-Reset": {"Sun, 01 Mar 2026 12:05:00 GMT"}},
+			10, -1, now.Add(5 * time.Minute),
+		},
+		{
+			"prefixed header wins",
+			http.Header{"X-Ratelimit-Limit": {"100"}, "Ratelimit-Limit": {"50"}},
+			100, -1, time.Time{},
+		},
+		{
+			"invalid values ignored",
+			http.Header{"X-Ratelimit-Limit": {"lots"}, "X-Ratelimit-Remaining": {"-3"}, "X-Ratelimit-Reset": {"2"}},
+			-1, -1, now.Add(2 * time.Second),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := parseRateLimitHeaders(tt.header, now)
+			if !ok {
+				t.Fatal("no rate-limit headers found")
+			}
+			if info.Limit != tt.limit || info.Remaining != tt.remaining || !info.Reset.Equal(tt.reset) {
+				t.Errorf("info = %+v, want limit %d, remaining %d, reset %v", info, tt.limit, tt.remaining, tt.reset)
+			}
+			if !info.UpdatedAt.Equal(now) {
+				t.Errorf("UpdatedAt = %v, want %v", info.UpdatedAt, now)
+			}
+		})
+	}
+
+	for _, header := range []http.Header{{}, {"X-Ratelimit-Reset": {"soon"}}} {
+		if info, ok := parseRateLimitHeaders(header, now); ok {
+			t.Errorf("parseRateLimitHeaders(%v) = %+v, want no info", header, info)
+		}
+	}
+}
+
+func TestRateLimitStatusKeepsLastHeaders(t *testing.T) {
+	api := newFakeAPI(t)
+	withHeaders := true
+	var mu sync.Mutex
+	api.handle("/GetBalance", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		mu.Lock()
+		if withHeaders {
+			w.Header().Set("X-RateLimit-Limit", "60")
+			w.Header().Set("X-RateLimit-Remaining", "59")
+		}
+		mu.Unlock()
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "balance": 1.0})
+	})
+	client := newTestClient(t, api.URL, nil)
+	ctx := context.Background()
+
+	if info := client.RateLimitStatus(); info.Limit != -1 || info.Remaining != -1 || !info.UpdatedAt.IsZero() {
+		t.Errorf("RateLimitStatus before any response = %+v, want unknown", info)
+	}
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	mu.Lock()
+	withHeaders = false
+	mu.Unlock()
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if info := client.RateLimitStatus(); info.Limit != 60 || info.Remaining != 59 || info.UpdatedAt.IsZero() {
+		t.Errorf("RateLimitStatus = %+v, want the headers of the first response", info)
+	}
+}
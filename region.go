@@ -0,0 +1,118 @@
+package freecap
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultAPIURL is the API base URL used when neither APIURL nor Region
+// select another one
+const DefaultAPIURL = "https://freecap.su"
+
+// Region names an API endpoint in RegionURLs
+type Region string
+
+const (
+	// RegionDefault is the main FreeCap endpoint
+	RegionDefault Region = "default"
+	// RegionAuto probes every endpoint in RegionURLs when the client is
+	// created and picks the one that answers fastest
+	RegionAuto Region = "auto"
+)
+
+// RegionURLs maps regions to API base URLs. Only RegionDefault is known;
+// add entries here before creating clients if other endpoints are available.
+var RegionURLs = map[Region]string{
+	RegionDefault: DefaultAPIURL,
+}
+
+// regionProbeTimeout bounds each latency probe made for RegionAuto
+const regionProbeTimeout = 3 * time.Second
+
+// resolveAPIURL returns the base URL for the config. An APIURL other than the
+// default always wins over Region.
+func resolveAPIURL(config *ClientConfig, doer HTTPDoer, logger Logger) (string, error) {
+	if config.Region == "" || (config.APIURL != "" && config.APIURL != DefaultAPIURL) {
+		return config.APIURL, nil
+	}
+
+	if config.Region == RegionAuto {
+		// DryRun sends nothing, latency probes included
+		if config.DryRun {
+			return RegionURLs[RegionDefault], nil
+		}
+		return probeRegions(doer, logger), nil
+	}
+
+	apiURL, ok := RegionURLs[config.Region]
+	if !ok {
+		return "", NewFreeCapValidationError("Unknown region: " + string(config.Region))
+	}
+	return apiURL, nil
+}
+
+// probeRegions requests every region's base URL concurrently and returns the
+// URL of the fastest one to respond with any HTTP status. It falls back to
+// the default region when no endpoint answers.
+func probeRegions(doer HTTPDoer, logger Logger) string {
+	if len(RegionURLs) == 1 {
+		for _, apiURL := range RegionURLs {
+			return apiURL
+		}
+	}
+
+	type probe struct {
+		region  Region
+		apiURL  string
+		latency time.Duration
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		probes []probe
+	)
+	for region, apiURL := range RegionURLs {
+		wg.Add(1)
+		go func(region Region, apiURL string) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), regionProbeTimeout)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+			if err != nil {
+				return
+			}
+			start := time.Now()
+			resp, err := doer.Do(req)
+			if err != nil {
+				logger.Debug("Region %s probe failed: %v", string(region), err)
+				return
+			}
+			resp.Body.Close()
+
+			mu.Lock()
+			probes = append(probes, probe{region: region, apiURL: apiURL, latency: time.Since(start)})
+			mu.Unlock()
+		}(region, apiURL)
+	}
+	wg.Wait()
+
+	if len(probes) == 0 {
+		logger.Warning("No region endpoint answered, using the default region")
+		return RegionURLs[RegionDefault]
+	}
+
+	sort.Slice(probes, func(i, j int) bool {
+		if probes[i].latency != probes[j].latency {
+			return probes[i].latency < probes[j].latency
+		}
+		return probes[i].region < probes[j].region
+	})
+	logger.Info("Selected region %s (%v)", string(probes[0].region), probes[0].latency)
+	return probes[0].apiURL
+}
@@ -0,0 +1,74 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// setRegionURLs replaces RegionURLs for the duration of a test
+func setRegionURLs(t *testing.T, urls map[Region]string) {
+	t.Helper()
+	saved := RegionURLs
+	RegionURLs = urls
+	t.Cleanup(func() { RegionURLs = saved })
+}
+
+func TestRegionSelectsURL(t *testing.T) {
+	eu, us := newFakeAPI(t), newFakeAPI(t)
+	setRegionURLs(t, map[Region]string{RegionDefault: DefaultAPIURL, "eu": eu.URL, "us": us.URL})
+
+	client := newTestClient(t, DefaultAPIURL, func(config *ClientConfig) {
+		config.Region = "eu"
+	})
+	if _, err := client.CreateTask(context.Background(), hcaptchaTask(), HCaptcha); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if eu.calls("/CreateTask") != 1 || us.calls("/CreateTask") != 0 {
+		t.Errorf("/CreateTask calls = eu %d, us %d; want the request sent to eu", eu.calls("/CreateTask"), us.calls("/CreateTask"))
+	}
+}
+
+func TestExplicitAPIURLOverridesRegion(t *testing.T) {
+	api, eu := newFakeAPI(t), newFakeAPI(t)
+	setRegionURLs(t, map[Region]string{RegionDefault: DefaultAPIURL, "eu": eu.URL})
+
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.Region = "eu"
+	})
+	if _, err := client.CreateTask(context.Background(), hcaptchaTask(), HCaptcha); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if api.calls("/CreateTask") != 1 || eu.calls("/CreateTask") != 0 {
+		t.Errorf("/CreateTask calls = APIURL %d, eu %d; want the request sent to APIURL", api.calls("/CreateTask"), eu.calls("/CreateTask"))
+	}
+}
+
+func TestUnknownRegion(t *testing.T) {
+	config := NewClientConfig()
+	config.Region = "mars"
+	if _, err := NewFreeCapClient("test-api-key", config, nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewFreeCapClient(unknown region) = %v, want validation error", err)
+	}
+}
+
+func TestRegionAutoPicksFastest(t *testing.T) {
+	slow, fast := newFakeAPI(t), newFakeAPI(t)
+	slow.handle("/", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	setRegionURLs(t, map[Region]string{RegionDefault: slow.URL, "fast": fast.URL})
+
+	client := newTestClient(t, DefaultAPIURL, func(config *ClientConfig) {
+		config.Region = RegionAuto
+	})
+	if _, err := client.CreateTask(context.Background(), hcaptchaTask(), HCaptcha); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if fast.calls("/CreateTask") != 1 || slow.calls("/CreateTask") != 0 {
+		t.Errorf("/CreateTask calls = fast %d, slow %d; want the fastest region", fast.calls("/CreateTask"), slow.calls("/CreateTask"))
+	}
+}
@@ -0,0 +1,105 @@
+package freecap
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// requestIDHeader carries the correlation ID of a request
+const requestIDHeader = "X-Request-ID"
+
+// idempotencyKeyHeader lets the server deduplicate a retried CreateTask
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type requestIDKey struct{}
+
+type idempotencyKey struct{}
+
+// WithRequestID returns a context whose requests carry id in the X-Request-ID
+// header and in their log lines. Requests without one get a generated ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// requestID returns the request ID from ctx or a new random one
+func requestID(ctx context.Context) string {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return id
+	}
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// withIdempotencyKey returns a context whose requests, including all their
+// retry attempts, carry a new random Idempotency-Key
+func withIdempotencyKey(ctx context.Context) context.Context {
+	key, err := newUUID()
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// idempotencyKeyFromContext returns the key set by withIdempotencyKey
+func idempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKey{}).(string)
+	return key, ok
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// requestLogger returns a logger that tags every message with id
+func (c *FreeCapClient) requestLogger(id string) Logger {
+	if id == "" {
+		return c.logger
+	}
+	switch logger := c.logger.(type) {
+	case *SlogLogger:
+		return logger.With("request_id", id)
+	case *JSONLogger:
+		return logger.With("request_id", id)
+	}
+	return &prefixLogger{logger: c.logger, prefix: "[" + id + "] "}
+}
+
+// prefixLogger prepends a fixed prefix to every message
+type prefixLogger struct {
+	logger Logger
+	prefix string
+}
+
+func (p *prefixLogger) Debug(message string, args ...interface{}) {
+	p.logger.Debug(p.prefix+message, args...)
+}
+
+func (p *prefixLogger) Info(message string, args ...interface{}) {
+	p.logger.Info(p.prefix+message, args...)
+}
+
+func (p *prefixLogger) Warning(message string, args ...interface{}) {
+	p.logger.Warning(p.prefix+message, args...)
+}
+
+func (p *prefixLogger) Error(message string, args ...interface{}) {
+	p.logger.Error(p.prefix+message, args...)
+}
@@ -0,0 +1,105 @@
+package freecap
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// failOnce answers 503 to the first request and ok to the rest
+func failOnce(ok map[string]interface{}) fakeHandler {
+	var calls atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if calls.Add(1) == 1 {
+			writeTestJSON(w, 503, map[string]interface{}{"status": false, "error": "busy"})
+			return
+		}
+		writeTestJSON(w, 200, ok)
+	}
+}
+
+func TestRequestIDHeaderAndLogs(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", failOnce(map[string]interface{}{"status": true, "balance": 1.0}))
+	logger := &recordingLogger{}
+	client := newTestClientWithLogger(t, api.URL, logger, nil)
+
+	ctx := WithRequestID(context.Background(), "req-1234")
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+
+	requests := api.requestsTo("/GetBalance")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	for i, req := range requests {
+		if got := req.Header.Get("X-Request-ID"); got != "req-1234" {
+			t.Errorf("attempt %d X-Request-ID = %q, want req-1234", i+1, got)
+		}
+	}
+	if !logger.has(LevelWarning, "[req-1234] Server error 503") {
+		t.Errorf("retry warning is not tagged with the request ID:\n%s", logger.output())
+	}
+}
+
+func TestGeneratedRequestID(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", failOnce(map[string]interface{}{"status": true, "balance": 1.0}))
+	logger := &recordingLogger{}
+	client := newTestClientWithLogger(t, api.URL, logger, nil)
+
+	if _, err := client.GetBalance(context.Background()); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+
+	requests := api.requestsTo("/GetBalance")
+	id := requests[0].Header.Get("X-Request-ID")
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(id) {
+		t.Fatalf("generated X-Request-ID = %q, want 16 hex digits", id)
+	}
+	if retried := requests[1].Header.Get("X-Request-ID"); retried != id {
+		t.Errorf("retry X-Request-ID = %q, want the first attempt's %q", retried, id)
+	}
+	if !strings.Contains(logger.output(), "["+id+"]") {
+		t.Errorf("logs do not mention the generated ID %s:\n%s", id, logger.output())
+	}
+}
+
+func TestIdempotencyKeyStableAcrossRetries(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/CreateTask", failOnce(map[string]interface{}{"status": true, "taskId": "task-1"}))
+	api.handle("/GetBalance", respond(200, map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateTask(ctx, hcaptchaTask(), HCaptcha); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	if _, err := client.GetBalance(ctx); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+
+	creates := api.requestsTo("/CreateTask")
+	if len(creates) != 3 {
+		t.Fatalf("/CreateTask calls = %d, want a failed attempt, its retry and a second task", len(creates))
+	}
+	key := creates[0].Header.Get(idempotencyKeyHeader)
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(key) {
+		t.Fatalf("Idempotency-Key = %q, want a v4 UUID", key)
+	}
+	if retried := creates[1].Header.Get(idempotencyKeyHeader); retried != key {
+		t.Errorf("retry Idempotency-Key = %q, want the first attempt's %q", retried, key)
+	}
+	if next := creates[2].Header.Get(idempotencyKeyHeader); next == key || next == "" {
+		t.Errorf("second task Idempotency-Key = %q, want a new key", next)
+	}
+	if got := api.requestsTo("/GetBalance")[0].Header.Get(idempotencyKeyHeader); got != "" {
+		t.Errorf("GetBalance Idempotency-Key = %q, want none outside task creation", got)
+	}
+}
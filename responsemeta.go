@@ -0,0 +1,43 @@
+package freecap
+
+import (
+	"context"
+	"net/http"
+)
+
+// ResponseMeta is the HTTP metadata of the last response received for a
+// call, e.g. for showing rate-limit quota on a dashboard
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// Attempt is the 1-based attempt that produced the response
+	Attempt int
+}
+
+type responseMetaKey struct{}
+
+// withResponseMeta returns a context whose requests record their last
+// response into the returned ResponseMeta
+func withResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	meta := &ResponseMeta{}
+	return context.WithValue(ctx, responseMetaKey{}, meta), meta
+}
+
+// recordResponseMeta stores resp's metadata in the ResponseMeta on ctx, if any
+func recordResponseMeta(ctx context.Context, resp *http.Response, attempt int) {
+	meta, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	if !ok {
+		return
+	}
+	meta.StatusCode = resp.StatusCode
+	meta.Header = resp.Header.Clone()
+	meta.Attempt = attempt
+}
+
+// metaOrNil returns meta if a response was recorded into it
+func metaOrNil(meta *ResponseMeta) *ResponseMeta {
+	if meta.Attempt == 0 {
+		return nil
+	}
+	return meta
+}
@@ -0,0 +1,73 @@
+package freecap
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGetBalanceWithMeta(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		w.Header().Set("X-Quota-Remaining", "41")
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "balance": 2.5})
+	})
+	client := newTestClient(t, api.URL, nil)
+
+	balance, meta, err := client.GetBalanceWithMeta(context.Background())
+	if err != nil {
+		t.Fatalf("GetBalanceWithMeta: %v", err)
+	}
+	if balance != 2.5 {
+		t.Errorf("balance = %v, want 2.5", balance)
+	}
+	if meta == nil {
+		t.Fatal("meta = nil, want the response metadata")
+	}
+	if meta.StatusCode != 200 || meta.Attempt != 1 {
+		t.Errorf("meta = %+v, want status 200 from attempt 1", meta)
+	}
+	if got := meta.Header.Get("X-Quota-Remaining"); got != "41" {
+		t.Errorf("X-Quota-Remaining = %q, want 41", got)
+	}
+}
+
+func TestResponseMetaIsFromLastAttempt(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", failOnce(map[string]interface{}{"status": true, "balance": 1.0}))
+	client := newTestClient(t, api.URL, nil)
+
+	meta, err := client.PingWithMeta(context.Background())
+	if err != nil {
+		t.Fatalf("PingWithMeta: %v", err)
+	}
+	if meta == nil || meta.StatusCode != 200 || meta.Attempt != 2 {
+		t.Errorf("meta = %+v, want status 200 from the retried attempt 2", meta)
+	}
+}
+
+func TestResponseMetaOnError(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetBalance", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		w.Header().Set("X-Error-Code", "bad-key")
+		writeTestJSON(w, 401, map[string]interface{}{"status": false, "error": "Invalid API key"})
+	})
+	client := newTestClient(t, api.URL, nil)
+
+	meta, err := client.PingWithMeta(context.Background())
+	if err == nil {
+		t.Fatal("PingWithMeta succeeded, want an error for a 401")
+	}
+	if meta == nil || meta.StatusCode != 401 || meta.Header.Get("X-Error-Code") != "bad-key" {
+		t.Errorf("meta = %+v, want the 401 response and its headers", meta)
+	}
+}
+
+func TestResponseMetaNilWithoutResponse(t *testing.T) {
+	client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+		config.MaxRetries = 0
+	})
+	if _, meta, err := client.GetBalanceWithMeta(context.Background()); err == nil || meta != nil {
+		t.Errorf("GetBalanceWithMeta = %+v, %v; want nil metadata and an error", meta, err)
+	}
+}
@@ -0,0 +1,38 @@
+package freecap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RqDataProvider fetches fresh hCaptcha rqdata, which for sites like Discord
+// is short-lived and must be obtained right before each solve.
+// Implementations must be safe for concurrent use.
+type RqDataProvider interface {
+	Fetch(ctx context.Context) (string, error)
+}
+
+// rqDataPending reports whether task's rqdata is left to the configured
+// provider: an hCaptcha task created without rqdata
+func (c *FreeCapClient) rqDataPending(task *CaptchaTask, captchaType CaptchaType) bool {
+	return c.config.RqDataProvider != nil && captchaType == HCaptcha && task.RqData == ""
+}
+
+// fetchRqData gets fresh rqdata from the configured provider. It is called
+// once the task is validated and about to be submitted, so invalid tasks
+// never reach the provider and the rqdata is as fresh as possible.
+func (c *FreeCapClient) fetchRqData(ctx context.Context) (string, error) {
+	rqdata, err := c.config.RqDataProvider.Fetch(ctx)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("failed to fetch rqdata: %w", err)
+	}
+	rqdata = strings.TrimSpace(rqdata)
+	if rqdata == "" {
+		return "", NewFreeCapValidationError("RqData provider returned empty rqdata")
+	}
+	return rqdata, nil
+}
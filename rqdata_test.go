@@ -0,0 +1,172 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubRqData is an RqDataProvider returning fixed rqdata or an error
+type stubRqData struct {
+	rqdata string
+	err    error
+	calls  atomic.Int32
+
+	mu      sync.Mutex
+	fetched []time.Time
+}
+
+func (s *stubRqData) Fetch(ctx context.Context) (string, error) {
+	s.calls.Add(1)
+	s.mu.Lock()
+	s.fetched = append(s.fetched, time.Now())
+	s.mu.Unlock()
+	return s.rqdata, s.err
+}
+
+// sentRqData returns the rqData of the nth /CreateTask request
+func sentRqData(t *testing.T, api *fakeAPI, n int) interface{} {
+	t.Helper()
+	creates := api.requestsTo("/CreateTask")
+	if len(creates) <= n {
+		t.Fatalf("/CreateTask calls = %d, want at least %d", len(creates), n+1)
+	}
+	return creates[n].Body["payload"].(map[string]interface{})["rqData"]
+}
+
+func TestRqDataProviderFillsTask(t *testing.T) {
+	api := newFakeAPI(t)
+	provider := &stubRqData{rqdata: " fresh-rqdata "}
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.RqDataProvider = provider
+	})
+	ctx := context.Background()
+
+	task := hcaptchaTask()
+	if _, err := client.CreateTask(ctx, task, HCaptcha); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if n := provider.calls.Load(); n != 1 {
+		t.Errorf("provider calls = %d, want 1", n)
+	}
+	if got := sentRqData(t, api, 0); got != "fresh-rqdata" {
+		t.Errorf("rqData = %v, want the provider's trimmed value", got)
+	}
+	if task.RqData != "" {
+		t.Errorf("caller's task RqData = %q, want it left unchanged", task.RqData)
+	}
+
+	// Tasks with their own rqdata and other captcha types skip the provider
+	task.RqData = "caller-rqdata"
+	if _, err := client.CreateTask(ctx, task, HCaptcha); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if got := sentRqData(t, api, 1); got != "caller-rqdata" {
+		t.Errorf("rqData = %v, want the task's own value", got)
+	}
+	if _, err := client.CreateTask(ctx, &CaptchaTask{Sitekey: "sitekey", Siteurl: "example.com"}, Turnstile); err != nil {
+		t.Fatalf("CreateTask(Turnstile): %v", err)
+	}
+	if n := provider.calls.Load(); n != 1 {
+		t.Errorf("provider calls = %d, want only the first task to fetch", n)
+	}
+}
+
+func TestRqDataProviderErrors(t *testing.T) {
+	fetchErr := errors.New("discord unreachable")
+	tests := []struct {
+		name     string
+		provider *stubRqData
+		want     error
+	}{
+		{"fetch failure", &stubRqData{err: fetchErr}, fetchErr},
+		{"empty rqdata", &stubRqData{rqdata: "  "}, ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			client := newTestClient(t, api.URL, func(config *ClientConfig) {
+				config.RqDataProvider = tt.provider
+			})
+			if _, err := client.CreateTask(context.Background(), hcaptchaTask(), HCaptcha); !errors.Is(err, tt.want) {
+				t.Errorf("CreateTask = %v, want %v", err, tt.want)
+			}
+			if n := api.calls("/CreateTask"); n != 0 {
+				t.Errorf("/CreateTask calls = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestSolveDiscordHCaptchaUsesRqDataProvider(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", respond(200, taskSolved("P1_token")))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.RqDataProvider = &stubRqData{rqdata: "discord-rqdata"}
+	})
+
+	if _, err := client.SolveDiscordHCaptcha(context.Background(), "", "gsk_key", "", 0); err != nil {
+		t.Fatalf("SolveDiscordHCaptcha: %v", err)
+	}
+	if got := sentRqData(t, api, 0); got != "discord-rqdata" {
+		t.Errorf("rqData = %v, want the provider's value", got)
+	}
+}
+
+func TestRqDataProviderNotCalledForInvalidTask(t *testing.T) {
+	api := newFakeAPI(t)
+	provider := &stubRqData{rqdata: "fresh-rqdata"}
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.RqDataProvider = provider
+	})
+
+	invalid := []*CaptchaTask{
+		{Siteurl: "discord.com"},
+		{Sitekey: "a9b5fb07-92ff-493f-86fe-352a2803b3df"},
+		{Sitekey: "a9b5fb07-92ff-493f-86fe-352a2803b3df", Siteurl: "discord.com", Discord: true},
+		{Sitekey: "a9b5fb07-92ff-493f-86fe-352a2803b3df", Siteurl: "discord.com", Proxy: "not a proxy"},
+	}
+	for _, task := range invalid {
+		if _, err := client.CreateTask(context.Background(), task, HCaptcha); !errors.Is(err, ErrValidation) {
+			t.Errorf("CreateTask(%+v) = %v, want validation error", task, err)
+		}
+	}
+	if n := provider.calls.Load(); n != 0 {
+		t.Errorf("provider calls = %d, want 0 for invalid tasks", n)
+	}
+	if n := api.calls("/CreateTask"); n != 0 {
+		t.Errorf("/CreateTask calls = %d, want 0", n)
+	}
+}
+
+func TestRqDataFetchedAfterGroqWait(t *testing.T) {
+	const gap = 80 * time.Millisecond
+
+	api := newFakeAPI(t)
+	provider := &stubRqData{rqdata: "fresh-rqdata"}
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.RqDataProvider = provider
+		config.GroqKeyInterval = gap
+	})
+
+	task := hcaptchaTask()
+	task.GroqAPIKey = "gsk_shared"
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateTask(context.Background(), task, HCaptcha); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	// The second task waits out the Groq gap before its rqdata is fetched
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.fetched) != 2 {
+		t.Fatalf("provider calls = %d, want 2", len(provider.fetched))
+	}
+	if spacing := provider.fetched[1].Sub(provider.fetched[0]); spacing < gap-5*time.Millisecond {
+		t.Errorf("rqdata fetches were %v apart, want the second after the %v Groq wait", spacing, gap)
+	}
+}
@@ -0,0 +1,81 @@
+package freecap
+
+// ResponseSchema names the fields read from API responses, so the client
+// keeps working if the API renames them. Empty fields use the defaults.
+type ResponseSchema struct {
+	// Status holds the task status, or true on successful calls (default "status")
+	Status string
+	// TaskID holds the task ID (default "taskId")
+	TaskID string
+	// Solution holds the solution of a solved task (default "solution"). It
+	// is either a string or an object holding the token under one of
+	// SolutionKeys.
+	Solution string
+	// SolutionKeys lists the keys checked in order when the solution is an
+	// object (default "token", then "gRecaptchaResponse")
+	SolutionKeys []string
+	// Error lists the fields checked in order for an error message
+	// (default "error", then "Error")
+	Error []string
+}
+
+// DefaultResponseSchema returns the field names used by the FreeCap API
+func DefaultResponseSchema() ResponseSchema {
+	return ResponseSchema{
+		Status:       "status",
+		TaskID:       "taskId",
+		Solution:     "solution",
+		SolutionKeys: []string{"token", "gRecaptchaResponse"},
+		Error:        []string{"error", "Error"},
+	}
+}
+
+// withDefaults returns a copy of s with empty field names set to the defaults
+func (s *ResponseSchema) withDefaults() ResponseSchema {
+	schema := DefaultResponseSchema()
+	if s == nil {
+		return schema
+	}
+	if s.Status != "" {
+		schema.Status = s.Status
+	}
+	if s.TaskID != "" {
+		schema.TaskID = s.TaskID
+	}
+	if s.Solution != "" {
+		schema.Solution = s.Solution
+	}
+	if len(s.SolutionKeys) > 0 {
+		schema.SolutionKeys = append([]string(nil), s.SolutionKeys...)
+	}
+	if len(s.Error) > 0 {
+		schema.Error = append([]string(nil), s.Error...)
+	}
+	return schema
+}
+
+// errorMessage returns the first non-empty error message in data
+func (s ResponseSchema) errorMessage(data map[string]interface{}) (string, bool) {
+	for _, field := range s.Error {
+		if errStr, ok := data[field].(string); ok && errStr != "" {
+			return errStr, true
+		}
+	}
+	return "", false
+}
+
+// solutionValue extracts the solution from a flat string or from a nested
+// object under one of SolutionKeys
+func (s ResponseSchema) solutionValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case map[string]interface{}:
+		for _, key := range s.SolutionKeys {
+			if token, ok := v[key].(string); ok && token != "" {
+				return token, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,101 @@
+package freecap
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResponseSchemaAlternateFieldNames(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/CreateTask", respond(200, map[string]interface{}{"state": true, "id": "task-9"}))
+	api.handle("/GetTask", sequence(
+		map[string]interface{}{"state": "processing"},
+		map[string]interface{}{"state": "solved", "result": map[string]interface{}{"captcha": "P1_token"}},
+	))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.ResponseSchema = &ResponseSchema{
+			Status:       "state",
+			TaskID:       "id",
+			Solution:     "result",
+			SolutionKeys: []string{"captcha"},
+		}
+	})
+
+	solution, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0)
+	if err != nil {
+		t.Fatalf("SolveCaptcha: %v", err)
+	}
+	if solution != "P1_token" {
+		t.Errorf("solution = %q, want %q", solution, "P1_token")
+	}
+	if got := api.requestsTo("/GetTask")[0].Body["taskId"]; got != "task-9" {
+		t.Errorf("/GetTask taskId = %v, want task-9", got)
+	}
+}
+
+func TestResponseSchemaAlternateErrorField(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/CreateTask", respond(400, map[string]interface{}{"status": false, "message": "sitekey rejected"}))
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.ResponseSchema = &ResponseSchema{Error: []string{"message"}}
+	})
+
+	_, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0)
+	if err == nil || !strings.Contains(err.Error(), "sitekey rejected") {
+		t.Errorf("SolveCaptcha = %v, want the message field in the error", err)
+	}
+}
+
+func TestResponseSchemaDefaults(t *testing.T) {
+	var schema *ResponseSchema
+	got := schema.withDefaults()
+	want := DefaultResponseSchema()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nil schema = %+v, want %+v", got, want)
+	}
+
+	partial := (&ResponseSchema{TaskID: "id"}).withDefaults()
+	if partial.TaskID != "id" || partial.Status != "status" || partial.Solution != "solution" {
+		t.Errorf("partial schema = %+v, want only TaskID overridden", partial)
+	}
+}
+
+func TestFlatAndNestedSolutions(t *testing.T) {
+	tests := []struct {
+		name     string
+		solution interface{}
+	}{
+		{"flat", "P1_token"},
+		{"nested token", map[string]interface{}{"token": "P1_token", "userAgent": "Mozilla/5.0"}},
+		{"nested gRecaptchaResponse", map[string]interface{}{"gRecaptchaResponse": "P1_token"}},
+		{"first key wins", map[string]interface{}{"token": "P1_token", "gRecaptchaResponse": "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.handle("/GetTask", respond(200, map[string]interface{}{"status": "solved", "solution": tt.solution}))
+			client := newTestClient(t, api.URL, nil)
+
+			solution, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0)
+			if err != nil {
+				t.Fatalf("SolveCaptcha: %v", err)
+			}
+			if solution != "P1_token" {
+				t.Errorf("solution = %q, want %q", solution, "P1_token")
+			}
+		})
+	}
+}
+
+func TestNestedSolutionWithoutKnownKey(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", respond(200, map[string]interface{}{"status": "solved", "solution": map[string]interface{}{"value": "P1_token"}}))
+	client := newTestClient(t, api.URL, nil)
+
+	_, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0)
+	if err == nil || !strings.Contains(err.Error(), "token, gRecaptchaResponse") {
+		t.Errorf("SolveCaptcha = %v, want an error naming the solution keys", err)
+	}
+}
@@ -0,0 +1,112 @@
+package freecap
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// prioritySemaphore limits concurrent solves, handing freed slots to the
+// highest-priority waiter first and to earlier waiters among equals
+type prioritySemaphore struct {
+	mu      sync.Mutex
+	free    int
+	waiters waiterQueue
+	seq     uint64
+}
+
+type slotWaiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+// newPrioritySemaphore creates a semaphore with size slots
+func newPrioritySemaphore(size int) *prioritySemaphore {
+	return &prioritySemaphore{free: size}
+}
+
+// acquire takes a slot, waiting until one is free, ctx is done or closed is
+// closed. A waiter that gives up is removed from the queue.
+func (s *prioritySemaphore) acquire(ctx context.Context, priority int, closed <-chan struct{}) error {
+	s.mu.Lock()
+	if s.free > 0 && len(s.waiters) == 0 {
+		s.free--
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.seq++
+	w := &slotWaiter{priority: priority, seq: s.seq, ready: make(chan struct{})}
+	heap.Push(&s.waiters, w)
+	s.mu.Unlock()
+
+	var err error
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-closed:
+		err = ErrClientClosed
+	}
+
+	s.mu.Lock()
+	if w.index >= 0 {
+		heap.Remove(&s.waiters, w.index)
+		s.mu.Unlock()
+		return err
+	}
+	s.mu.Unlock()
+
+	// The slot was granted while giving up; pass it on
+	s.release()
+	return err
+}
+
+// release returns a slot, waking the next waiter if there is one
+func (s *prioritySemaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.waiters) == 0 {
+		s.free++
+		return
+	}
+	w := heap.Pop(&s.waiters).(*slotWaiter)
+	close(w.ready)
+}
+
+// waiterQueue is a heap of slot waiters ordered by priority, then arrival
+type waiterQueue []*slotWaiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waiterQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waiterQueue) Push(x interface{}) {
+	w := x.(*slotWaiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waiterQueue) Pop() interface{} {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	w.index = -1
+	*q = old[:len(old)-1]
+	return w
+}
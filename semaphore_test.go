@@ -0,0 +1,182 @@
+package freecap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentTasksLimitsActiveSolves(t *testing.T) {
+	const limit, solves = 2, 8
+
+	api := newFakeAPI(t)
+	var nextID, active, peak atomic.Int32
+	api.handle("/CreateTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "taskId": fmt.Sprintf("task-%d", nextID.Add(1))})
+	})
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		time.Sleep(10 * time.Millisecond)
+		active.Add(-1)
+		writeTestJSON(w, 200, taskSolved("P1_token"))
+	})
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.MaxConcurrentTasks = limit
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, solves)
+	for i := 0; i < solves; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("SolveCaptcha: %v", err)
+	}
+
+	if n := api.calls("/CreateTask"); n != solves {
+		t.Errorf("/CreateTask calls = %d, want %d", n, solves)
+	}
+	if p := peak.Load(); p > limit {
+		t.Errorf("peak active solves = %d, want at most %d", p, limit)
+	}
+}
+
+// waitForWaiters blocks until n solves are queued on the semaphore
+func waitForWaiters(t *testing.T, s *prioritySemaphore, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		queued := len(s.waiters)
+		s.mu.Unlock()
+		if queued >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d waiters queued after 5s, want %d", queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrioritySemaphoreOrder(t *testing.T) {
+	s := newPrioritySemaphore(1)
+	if err := s.acquire(context.Background(), 0, nil); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	waiters := []struct {
+		name     string
+		priority int
+	}{
+		{"low", 0}, {"high-1", 5}, {"mid", 2}, {"high-2", 5},
+	}
+	for i, w := range waiters {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.acquire(context.Background(), w.priority, nil); err != nil {
+				t.Errorf("acquire(%s): %v", w.name, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, w.name)
+			mu.Unlock()
+			s.release()
+		}()
+		waitForWaiters(t, s, i+1)
+	}
+
+	s.release()
+	wg.Wait()
+
+	want := []string{"high-1", "high-2", "mid", "low"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("slot order = %v, want %v", order, want)
+	}
+}
+
+func TestPriorityOrdersQueuedSolves(t *testing.T) {
+	api := newFakeAPI(t)
+	release := make(chan struct{})
+	api.handle("/GetTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		if body["taskId"] == "task-blocker" {
+			<-release
+		}
+		writeTestJSON(w, 200, taskSolved("P1_token"))
+	})
+	api.handle("/CreateTask", func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		sitekey := body["payload"].(map[string]interface{})["websiteKey"]
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "taskId": fmt.Sprintf("task-%v", sitekey)})
+	})
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.MaxConcurrentTasks = 1
+	})
+
+	solve := func(sitekey string, priority int) {
+		task := hcaptchaTask()
+		task.Sitekey = sitekey
+		task.Priority = priority
+		if _, err := client.SolveCaptcha(context.Background(), task, HCaptcha, 0, 0); err != nil {
+			t.Errorf("SolveCaptcha(%s): %v", sitekey, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		solve("blocker", 0)
+	}()
+	waitForCalls(t, api, "/GetTask", 1)
+
+	queued := []struct {
+		sitekey  string
+		priority int
+	}{
+		{"low", -1}, {"normal", 0}, {"urgent", 10},
+	}
+	for i, q := range queued {
+		q := q
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			solve(q.sitekey, q.priority)
+		}()
+		waitForWaiters(t, client.taskSlots, i+1)
+	}
+
+	close(release)
+	wg.Wait()
+
+	var order []string
+	for _, req := range api.requestsTo("/CreateTask") {
+		order = append(order, fmt.Sprint(req.Body["payload"].(map[string]interface{})["websiteKey"]))
+	}
+	want := []string{"blocker", "urgent", "normal", "low"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("task creation order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,74 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+)
+
+// errShutdownCancelled is the cancellation cause of solves cut short by
+// Shutdown, whose tasks are left on the server and in the TaskStore
+var errShutdownCancelled = errors.New("client shut down before the solve finished")
+
+// beginSolve registers an in-flight solve, waiting for a free slot when
+// MaxConcurrentTasks is set; higher priority solves get freed slots first. It
+// returns a context that is cancelled if Shutdown gives up waiting, and a
+// function that must be called when the solve ends. New solves are refused
+// once Shutdown has started.
+func (c *FreeCapClient) beginSolve(ctx context.Context, priority int) (context.Context, func(), error) {
+	c.shutdownMu.Lock()
+	if c.shuttingDown || c.closed.Load() {
+		c.shutdownMu.Unlock()
+		return nil, nil, ErrClientClosed
+	}
+	c.inflight.Add(1)
+	c.shutdownMu.Unlock()
+
+	if c.taskSlots != nil {
+		if err := c.taskSlots.acquire(ctx, priority, c.shutdownCtx.Done()); err != nil {
+			c.inflight.Done()
+			return nil, nil, err
+		}
+	}
+
+	solveCtx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(c.shutdownCtx, func() { cancel(errShutdownCancelled) })
+
+	return solveCtx, func() {
+		stop()
+		cancel(nil)
+		if c.taskSlots != nil {
+			c.taskSlots.release()
+		}
+		c.inflight.Done()
+	}, nil
+}
+
+// Shutdown stops the client from accepting new solves and waits for in-flight
+// solves to finish. If ctx expires first, the remaining solves are cancelled
+// and ctx's error is returned; their tasks are not deleted, so they can be
+// resumed from the TaskStore. The client is closed either way.
+func (c *FreeCapClient) Shutdown(ctx context.Context) error {
+	c.shutdownMu.Lock()
+	c.shuttingDown = true
+	c.shutdownMu.Unlock()
+
+	c.logger.Debug("Shutting down, waiting for in-flight solves")
+
+	done := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	defer c.Close()
+
+	select {
+	case <-done:
+		c.cancelInflight()
+		return nil
+	case <-ctx.Done():
+		c.logger.Warning("Shutdown deadline reached, cancelling in-flight solves")
+		c.cancelInflight()
+		return ctx.Err()
+	}
+}
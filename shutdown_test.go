@@ -0,0 +1,112 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitForCalls blocks until the fake API has received n requests to path
+func waitForCalls(t *testing.T, api *fakeAPI, path string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for api.calls(path) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d requests to %s", n, path)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestShutdownWaitsForSolve(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", sequence(taskStatus(Processing), taskStatus(Processing), taskSolved("P1_token")))
+	client := newTestClient(t, api.URL, nil)
+
+	results := client.SolveCaptchaAsync(context.Background(), hcaptchaTask(), HCaptcha, 0, 0)
+	waitForCalls(t, api, "/GetTask", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown = %v, want nil once the solve finishes", err)
+	}
+
+	// Shutdown returned, so the solve has already delivered its result
+	select {
+	case result := <-results:
+		if result.Err != nil || result.Solution != "P1_token" {
+			t.Errorf("in-flight solve = %+v, want P1_token", result)
+		}
+	default:
+		t.Fatal("Shutdown returned before the in-flight solve finished")
+	}
+
+	if _, err := client.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 0, 0); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("SolveCaptcha after Shutdown = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", respond(200, taskStatus(Processing)))
+	client := newTestClient(t, api.URL, nil)
+
+	results := client.SolveCaptchaAsync(context.Background(), hcaptchaTask(), HCaptcha, 0, 0)
+	waitForCalls(t, api, "/GetTask", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want context.DeadlineExceeded", err)
+	}
+
+	select {
+	case result := <-results:
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("cancelled solve = %v, want context.Canceled", result.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("in-flight solve kept running after Shutdown gave up")
+	}
+}
+
+func TestShutdownKeepsCancelledTasks(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle("/GetTask", respond(200, taskStatus(Processing)))
+	api.handle("/DeleteTask", respond(200, map[string]interface{}{"status": true}))
+	store, err := NewFileTaskStore(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatalf("NewFileTaskStore: %v", err)
+	}
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.TaskStore = store
+	})
+
+	// Cancel the way a Shutdown past its deadline does, but keep the client
+	// open so a DeleteTask would not be refused
+	ctx, done, err := client.beginSolve(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("beginSolve: %v", err)
+	}
+	defer done()
+	client.storeTask("task-1", HCaptcha)
+	go func() {
+		waitForCalls(t, api, "/GetTask", 1)
+		client.cancelInflight()
+	}()
+
+	if _, err := client.waitForResult(ctx, "task-1", HCaptcha, 5*time.Second, 5*time.Millisecond, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("waitForResult = %v, want context.Canceled", err)
+	}
+
+	// The task may still be solved, so it stays resumable after a restart
+	if n := api.calls("/DeleteTask"); n != 0 {
+		t.Errorf("got %d DeleteTask calls, want none after a shutdown", n)
+	}
+	if pending, err := store.List(); err != nil || len(pending) != 1 {
+		t.Errorf("pending tasks = %v, %v; want task-1 kept", pending, err)
+	}
+}
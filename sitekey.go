@@ -0,0 +1,56 @@
+package freecap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// sitekeyFormats are the usual sitekey shapes checked under StrictValidation
+var sitekeyFormats = map[CaptchaType]struct {
+	pattern     *regexp.Regexp
+	description string
+}{
+	HCaptcha: {
+		regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		"a UUID such as a9b5fb07-92ff-493f-86fe-352a2803b3df",
+	},
+	Turnstile: {
+		regexp.MustCompile(`^0x[0-9A-Za-z_-]{8,}$`),
+		"0x followed by the key, such as 0x4AAAAAAA...",
+	},
+	RecaptchaV2: {
+		regexp.MustCompile(`^6[0-9A-Za-z_-]{39}$`),
+		"40 characters starting with 6",
+	},
+	RecaptchaV3: {
+		regexp.MustCompile(`^6[0-9A-Za-z_-]{39}$`),
+		"40 characters starting with 6",
+	},
+}
+
+// checkSitekey rejects sitekeys that cannot be valid before a task is
+// created. Keys containing whitespace or looking like a URL are always
+// rejected; with StrictValidation, known types must also match their usual
+// format.
+func (c *FreeCapClient) checkSitekey(sitekey string, captchaType CaptchaType) error {
+	if sitekey == "" {
+		return nil
+	}
+
+	if strings.ContainsAny(sitekey, " \t\r\n") {
+		return NewFreeCapValidationError(fmt.Sprintf("sitekey %q contains whitespace", sitekey))
+	}
+	if strings.Contains(sitekey, "://") {
+		return NewFreeCapValidationError(fmt.Sprintf("sitekey %q looks like a URL; did you swap sitekey and siteurl?", sitekey))
+	}
+
+	if !c.config.StrictValidation {
+		return nil
+	}
+	format, ok := sitekeyFormats[captchaType]
+	if ok && !format.pattern.MatchString(sitekey) {
+		return NewFreeCapValidationError(fmt.Sprintf("sitekey %q is not a valid %s sitekey (expected %s)", sitekey, string(captchaType), format.description))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckSitekey(t *testing.T) {
+	recaptchaKey := "6Le" + strings.Repeat("x", 37)
+	tests := []struct {
+		name        string
+		sitekey     string
+		captchaType CaptchaType
+		strictOK    bool
+		lenientOK   bool
+	}{
+		{"hCaptcha UUID", "a9b5fb07-92ff-493f-86fe-352a2803b3df", HCaptcha, true, true},
+		{"hCaptcha not a UUID", "a9b5fb07", HCaptcha, false, true},
+		{"Turnstile key", "0x4AAAAAAABkMYinukE8nzY", Turnstile, true, true},
+		{"Turnstile missing 0x", "4AAAAAAABkMYinukE8nzY", Turnstile, false, true},
+		{"Turnstile too short", "0x4AAA", Turnstile, false, true},
+		{"reCAPTCHA v2 key", recaptchaKey, RecaptchaV2, true, true},
+		{"reCAPTCHA v3 key", recaptchaKey, RecaptchaV3, true, true},
+		{"reCAPTCHA too short", "6Lexxxx", RecaptchaV2, false, true},
+		{"reCAPTCHA wrong prefix", "5" + recaptchaKey[1:], RecaptchaV3, false, true},
+		{"type without a format", "anything-goes", Geetest, true, true},
+		{"empty", "", HCaptcha, true, true},
+		{"whitespace", "a9b5fb07 92ff", HCaptcha, false, false},
+		{"trailing newline", "0x4AAAAAAABkMYinukE8nzY\n", Turnstile, false, false},
+		{"URL", "https://discord.com", Geetest, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, strict := range []bool{true, false} {
+				client := newTestClient(t, unusedAPIURL, func(config *ClientConfig) {
+					config.StrictValidation = strict
+				})
+				wantOK := tt.lenientOK
+				if strict {
+					wantOK = tt.strictOK
+				}
+				err := client.checkSitekey(tt.sitekey, tt.captchaType)
+				if wantOK && err != nil {
+					t.Errorf("strict=%v: checkSitekey(%q, %s) = %v, want nil", strict, tt.sitekey, tt.captchaType, err)
+				}
+				if !wantOK && !errors.Is(err, ErrValidation) {
+					t.Errorf("strict=%v: checkSitekey(%q, %s) = %v, want validation error", strict, tt.sitekey, tt.captchaType, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStrictSitekeyRejectedBeforeCreateTask(t *testing.T) {
+	api := newFakeAPI(t)
+	client := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.StrictValidation = true
+	})
+
+	task := hcaptchaTask()
+	task.Sitekey = "not-a-uuid"
+	_, err := client.CreateTask(context.Background(), task, HCaptcha)
+	if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), "expected a UUID") {
+		t.Errorf("CreateTask = %v, want a validation error describing the expected format", err)
+	}
+	if n := api.calls("/CreateTask"); n != 0 {
+		t.Errorf("/CreateTask calls = %d, want 0", n)
+	}
+}
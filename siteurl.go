@@ -0,0 +1,98 @@
+package freecap
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// SiteurlMode controls how task siteurls are normalized before being sent
+type SiteurlMode int
+
+const (
+	// SiteurlRaw sends the siteurl exactly as given, without validation.
+	// This is the default, as reCAPTCHA and Turnstile need the full page URL.
+	SiteurlRaw SiteurlMode = iota
+	// SiteurlHost sends only the host (and port, if any), e.g. "discord.com"
+	// for "https://discord.com/login?x=1"
+	SiteurlHost
+	// SiteurlOrigin keeps the scheme, e.g. "https://discord.com". A bare host
+	// gets https.
+	SiteurlOrigin
+)
+
+// NormalizeSiteurl reduces a siteurl to the form selected by mode. Paths,
+// queries, fragments and credentials are dropped and the host is lowercased.
+// It returns a validation error when the input has no plausible host.
+func NormalizeSiteurl(raw string, mode SiteurlMode) (string, error) {
+	if mode == SiteurlRaw {
+		return raw, nil
+	}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", NewFreeCapValidationError("siteurl cannot be empty")
+	}
+	if strings.ContainsAny(raw, " \t\r\n") {
+		return "", NewFreeCapValidationError(fmt.Sprintf("siteurl %q contains whitespace", raw))
+	}
+
+	withScheme := raw
+	if !strings.Contains(raw, "://") {
+		withScheme = "https://" + raw
+	}
+
+	u, err := url.Parse(withScheme)
+	if err != nil {
+		return "", NewFreeCapValidationError(fmt.Sprintf("invalid siteurl %q: %v", raw, err))
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", NewFreeCapValidationError(fmt.Sprintf("siteurl %q must use http or https", raw))
+	}
+
+	hostname := strings.ToLower(u.Hostname())
+	if !isPlausibleHost(hostname) {
+		return "", NewFreeCapValidationError(fmt.Sprintf("siteurl %q does not contain a valid host", raw))
+	}
+
+	host := hostname
+	if port := u.Port(); port != "" {
+		host = net.JoinHostPort(hostname, port)
+	} else if strings.Contains(hostname, ":") {
+		host = "[" + hostname + "]"
+	}
+
+	if mode == SiteurlOrigin {
+		return scheme + "://" + host, nil
+	}
+	return host, nil
+}
+
+// isPlausibleHost reports whether host is an IP address, localhost or a
+// dotted domain name made of valid labels
+func isPlausibleHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	if net.ParseIP(host) != nil || host == "localhost" {
+		return true
+	}
+
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
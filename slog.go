@@ -0,0 +1,56 @@
+package freecap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// SlogLogger adapts a *slog.Logger to the Logger interface.
+//
+// The client logs printf-style messages, so the message and its args are
+// formatted into the record message. Attach structured fields with With.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a Logger backed by the given slog logger
+// (slog.Default() when nil)
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{logger: logger}
+}
+
+// With returns a SlogLogger that adds the given key/value pairs to every record
+func (s *SlogLogger) With(args ...interface{}) *SlogLogger {
+	return &SlogLogger{logger: s.logger.With(args...)}
+}
+
+func (s *SlogLogger) log(level slog.Level, message string, args ...interface{}) {
+	ctx := context.Background()
+	if !s.logger.Enabled(ctx, level) {
+		return
+	}
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	s.logger.Log(ctx, level, message)
+}
+
+func (s *SlogLogger) Debug(message string, args ...interface{}) {
+	s.log(slog.LevelDebug, message, args...)
+}
+
+func (s *SlogLogger) Info(message string, args ...interface{}) {
+	s.log(slog.LevelInfo, message, args...)
+}
+
+func (s *SlogLogger) Warning(message string, args ...interface{}) {
+	s.log(slog.LevelWarn, message, args...)
+}
+
+func (s *SlogLogger) Error(message string, args ...interface{}) {
+	s.log(slog.LevelError, message, args...)
+}
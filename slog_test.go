@@ -0,0 +1,62 @@
+package freecap
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := NewSlogLogger(slog.New(handler)).(*SlogLogger).With("component", "test")
+
+	logger.Debug("debug %d", 1)
+	logger.Info("info %d", 2)
+	logger.Warning("warning %d", 3)
+	logger.Error("error %d", 4)
+
+	want := []struct{ level, msg string }{
+		{"DEBUG", "debug 1"},
+		{"INFO", "info 2"},
+		{"WARN", "warning 3"},
+		{"ERROR", "error 4"},
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("record %d is not JSON: %v", i, err)
+		}
+		if record["level"] != want[i].level || record["msg"] != want[i].msg {
+			t.Errorf("record %d = %s/%v, want %s/%s", i, record["level"], record["msg"], want[i].level, want[i].msg)
+		}
+		if record["component"] != "test" {
+			t.Errorf("record %d lost the With attribute: %v", i, record)
+		}
+	}
+}
+
+func TestSlogLoggerRespectsHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	logger := NewSlogLogger(slog.New(handler))
+
+	logger.Debug("hidden debug")
+	logger.Info("hidden info")
+	logger.Warning("shown warning")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Errorf("records below the handler level were written:\n%s", out)
+	}
+	if !strings.Contains(out, "shown warning") {
+		t.Errorf("warning record missing:\n%s", out)
+	}
+}
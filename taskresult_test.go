@@ -0,0 +1,117 @@
+package freecap_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	freecap "github.com/freecap-su/Wrappers"
+	"github.com/freecap-su/Wrappers/testutil"
+)
+
+func TestGetTaskResultTyped(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		taskID   string
+		status   freecap.TaskStatus
+		solution string
+		errMsg   string
+	}{
+		{"solved", `{"status":"solved","taskId":"task-9","solution":"P1_token"}`, "task-9", freecap.Solved, "P1_token", ""},
+		{"status case", `{"status":"PROCESSING"}`, "task-1", freecap.Processing, "", ""},
+		{"nested solution", `{"status":"solved","solution":{"token":"nested-token"}}`, "task-1", freecap.Solved, "nested-token", ""},
+		{"built-in alias", `{"status":"ready","solution":"P1_token"}`, "task-1", freecap.Solved, "P1_token", ""},
+		{"configured alias", `{"status":"Finished","solution":"P1_token"}`, "task-1", freecap.Solved, "P1_token", ""},
+		{"configured failed alias", `{"status":"rejected","error":"bad sitekey"}`, "task-1", freecap.Failed, "", "bad sitekey"},
+		{"lowercase error key", `{"status":"failed","error":"unsolvable"}`, "task-1", freecap.Failed, "", "unsolvable"},
+		{"capitalized error key", `{"status":"failed","Error":"proxy banned"}`, "task-1", freecap.Failed, "", "proxy banned"},
+		{"lowercase error key wins", `{"status":"failed","error":"first","Error":"second"}`, "task-1", freecap.Failed, "", "first"},
+		{"empty error falls back", `{"status":"failed","error":"","Error":"second"}`, "task-1", freecap.Failed, "", "second"},
+		{"unknown status", `{"status":"queued"}`, "task-1", freecap.TaskStatus("queued"), "", ""},
+		{"no status", `{}`, "task-1", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewMockServer()
+			defer server.Close()
+			server.InjectFailure("/GetTask", 200, tt.body)
+
+			config := freecap.NewClientConfig()
+			config.SolvedStatuses = []string{"finished"}
+			config.FailedStatuses = []string{"rejected"}
+			client, err := server.NewClient(config)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer client.Close()
+
+			result, err := client.GetTaskResultTyped(context.Background(), " task-1 ")
+			if err != nil {
+				t.Fatalf("GetTaskResultTyped: %v", err)
+			}
+			if result.TaskID != tt.taskID || result.Status != tt.status || result.Solution != tt.solution || result.Error != tt.errMsg {
+				t.Errorf("result = {TaskID:%q Status:%q Solution:%q Error:%q}, want {%q %q %q %q}",
+					result.TaskID, result.Status, result.Solution, result.Error, tt.taskID, tt.status, tt.solution, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTaskResultRawKeepsUnknownFields(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.InjectFailure("/GetTask", 200, `{"status":"solved","solution":"P1_token","cost":0.002,"userAgent":"Mozilla/5.0","extra":{"ip":"203.0.113.7"}}`)
+
+	client, err := server.NewClient(nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.GetTaskResultTyped(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("GetTaskResultTyped: %v", err)
+	}
+	want := map[string]interface{}{
+		"status":    "solved",
+		"solution":  "P1_token",
+		"cost":      0.002,
+		"userAgent": "Mozilla/5.0",
+		"extra":     map[string]interface{}{"ip": "203.0.113.7"},
+	}
+	if !reflect.DeepEqual(result.Raw, want) {
+		t.Errorf("Raw = %v, want %v", result.Raw, want)
+	}
+}
+
+func TestGetTaskResultTypedFromMockTask(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.SetPendingPolls(1)
+	server.SetSolution("P1_mock")
+
+	client, err := server.NewClient(nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	taskID, err := client.CreateTask(ctx, &freecap.CaptchaTask{Sitekey: "sitekey", Siteurl: "example.com"}, freecap.Turnstile)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	for _, want := range []freecap.TaskStatus{freecap.Processing, freecap.Solved} {
+		result, err := client.GetTaskResultTyped(ctx, taskID)
+		if err != nil {
+			t.Fatalf("GetTaskResultTyped: %v", err)
+		}
+		if result.TaskID != taskID || result.Status != want {
+			t.Errorf("result = %+v, want %s for %s", result, want, taskID)
+		}
+		if want == freecap.Solved && result.Solution != "P1_mock" {
+			t.Errorf("Solution = %q, want P1_mock", result.Solution)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package freecap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TaskMeta describes a created task recorded in a TaskStore
+type TaskMeta struct {
+	CaptchaType CaptchaType `json:"captcha_type"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// TaskStore records created tasks until they reach a terminal status, so
+// tasks left pending by a crash or restart can be resumed with WaitForResult.
+// Implementations must be safe for concurrent use.
+type TaskStore interface {
+	Save(taskID string, meta TaskMeta) error
+	List() (map[string]TaskMeta, error)
+	Delete(taskID string) error
+}
+
+// FileTaskStore is a TaskStore backed by a JSON file. Every change rewrites
+// the file atomically.
+type FileTaskStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTaskStore creates a store at path; the file is created on first save
+func NewFileTaskStore(path string) (*FileTaskStore, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, NewFreeCapValidationError("Task store path cannot be empty")
+	}
+	return &FileTaskStore{path: path}, nil
+}
+
+// Save implements TaskStore
+func (s *FileTaskStore) Save(taskID string, meta TaskMeta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.load()
+	if err != nil {
+		return err
+	}
+	tasks[taskID] = meta
+	return s.write(tasks)
+}
+
+// List implements TaskStore
+func (s *FileTaskStore) List() (map[string]TaskMeta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Delete implements TaskStore
+func (s *FileTaskStore) Delete(taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tasks[taskID]; !ok {
+		return nil
+	}
+	delete(tasks, taskID)
+	return s.write(tasks)
+}
+
+// load reads the stored tasks, treating a missing file as empty
+func (s *FileTaskStore) load() (map[string]TaskMeta, error) {
+	tasks := make(map[string]TaskMeta)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tasks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task store: %w", err)
+	}
+	if len(data) == 0 {
+		return tasks, nil
+	}
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("invalid task store %s: %w", s.path, err)
+	}
+	return tasks, nil
+}
+
+// write replaces the store file via a temporary file and rename
+func (s *FileTaskStore) write(tasks map[string]TaskMeta) error {
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode task store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write task store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write task store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write task store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write task store: %w", err)
+	}
+	return nil
+}
+
+// storeTask records a created task in the configured TaskStore
+func (c *FreeCapClient) storeTask(taskID string, captchaType CaptchaType) {
+	if c.config.TaskStore == nil {
+		return
+	}
+	meta := TaskMeta{CaptchaType: captchaType, CreatedAt: time.Now()}
+	if err := c.config.TaskStore.Save(taskID, meta); err != nil {
+		c.logger.Warning("Failed to save task %s to task store: %v", taskID, err)
+	}
+}
+
+// storedTaskType returns the captcha type recorded for taskID in the
+// configured TaskStore, or an empty type when there is no entry
+func (c *FreeCapClient) storedTaskType(taskID string) CaptchaType {
+	if c.config.TaskStore == nil {
+		return ""
+	}
+	tasks, err := c.config.TaskStore.List()
+	if err != nil {
+		c.logger.Warning("Failed to read task store: %v", err)
+		return ""
+	}
+	return tasks[taskID].CaptchaType
+}
+
+// forgetTask removes a task that reached a terminal status from the TaskStore
+func (c *FreeCapClient) forgetTask(taskID string) {
+	if c.config.TaskStore == nil {
+		return
+	}
+	if err := c.config.TaskStore.Delete(taskID); err != nil {
+		c.logger.Warning("Failed to remove task %s from task store: %v", taskID, err)
+	}
+}
@@ -0,0 +1,99 @@
+package freecap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTaskStoreResumeAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	api := newFakeAPI(t)
+	api.handle("/GetTask", respond(200, taskStatus(Processing)))
+
+	// The first process creates a task and gives up on it before it is solved
+	store, err := NewFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("NewFileTaskStore: %v", err)
+	}
+	first := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.TaskStore = store
+	})
+	if _, err := first.SolveCaptcha(context.Background(), hcaptchaTask(), HCaptcha, 30*time.Millisecond, 0); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("SolveCaptcha = %v, want ErrTimeout", err)
+	}
+	first.Close()
+
+	// After a restart the pending task is read back from the file and resumed
+	api.handle("/GetTask", respond(200, taskSolved("P1_token")))
+	restarted, err := NewFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("NewFileTaskStore: %v", err)
+	}
+	pending, err := restarted.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	meta, ok := pending["task-1"]
+	if len(pending) != 1 || !ok {
+		t.Fatalf("pending tasks = %v, want task-1", pending)
+	}
+	if meta.CaptchaType != HCaptcha || meta.CreatedAt.IsZero() {
+		t.Errorf("task-1 meta = %+v, want hcaptcha with a creation time", meta)
+	}
+
+	second := newTestClient(t, api.URL, func(config *ClientConfig) {
+		config.TaskStore = restarted
+	})
+	solution, err := second.WaitForResult(context.Background(), "task-1", 0, 0)
+	if err != nil {
+		t.Fatalf("WaitForResult: %v", err)
+	}
+	if solution != "P1_token" {
+		t.Errorf("solution = %q, want %q", solution, "P1_token")
+	}
+	if stats := second.StatsByType()[HCaptcha]; stats.Solved != 1 {
+		t.Errorf("resumed hCaptcha stats = %+v, want 1 solved", stats)
+	}
+
+	pending, err = restarted.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("pending tasks after resuming = %v, want none", pending)
+	}
+}
+
+func TestFileTaskStoreMissingAndInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileTaskStore(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("NewFileTaskStore: %v", err)
+	}
+	if tasks, err := store.List(); err != nil || len(tasks) != 0 {
+		t.Errorf("List on a missing file = %v, %v; want empty", tasks, err)
+	}
+	if err := store.Delete("task-1"); err != nil {
+		t.Errorf("Delete of an unknown task = %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err = NewFileTaskStore(invalid)
+	if err != nil {
+		t.Fatalf("NewFileTaskStore: %v", err)
+	}
+	if _, err := store.List(); err == nil {
+		t.Error("List on an invalid file succeeded")
+	}
+
+	if _, err := NewFileTaskStore(" "); !errors.Is(err, ErrValidation) {
+		t.Errorf("NewFileTaskStore(empty path) = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,139 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	freecap "github.com/freecap-su/Wrappers"
+)
+
+// FakeClock is a freecap.Clock whose time only moves when Advance is called.
+// Pass it as ClientConfig.Clock to run solves through many poll intervals
+// or retry delays instantly:
+//
+//	clock := testutil.NewFakeClock(time.Now())
+//	config.Clock = clock
+//	go client.SolveCaptcha(ctx, task, freecap.HCaptcha, 0, 0)
+//	clock.BlockUntil(2) // solve timeout and poll ticker
+//	clock.Advance(3 * time.Second)
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending After channel or ticker
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewFakeClock creates a fake clock starting at now
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now implements freecap.Clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements freecap.Clock
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.addWaiter(&fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// NewTicker implements freecap.Clock
+func (c *FakeClock) NewTicker(d time.Duration) freecap.Ticker {
+	if d <= 0 {
+		panic("testutil: non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.addWaiter(w)
+	return &fakeTicker{clock: c, waiter: w}
+}
+
+// Advance moves the clock forward by d, firing every After channel and
+// ticker that comes due. Like time.Ticker, a ticker whose previous tick was
+// not received drops the new one.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			remaining = append(remaining, w)
+			continue
+		}
+
+		select {
+		case w.ch <- c.now:
+		default:
+		}
+		if w.period > 0 {
+			for !w.at.After(c.now) {
+				w.at = w.at.Add(w.period)
+			}
+			remaining = append(remaining, w)
+		}
+	}
+	c.waiters = remaining
+}
+
+// BlockUntil waits until at least n After channels or tickers are pending,
+// so a following Advance is not lost on code that has not started waiting
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) addWaiter(w *fakeWaiter) {
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+}
+
+func (c *FakeClock) removeWaiter(target *fakeWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, w := range c.waiters {
+		if w == target {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+func (t *fakeTicker) Stop()               { t.clock.removeWaiter(t.waiter) }
@@ -0,0 +1,356 @@
+// Package testutil provides an in-memory fake of the FreeCap API for testing
+// code that uses the client without reaching the real service:
+//
+//	server := testutil.NewMockServer()
+//	defer server.Close()
+//	server.SetSolution("P1_mock-token")
+//
+//	client, err := server.NewClient(nil)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	solution, err := client.SolveCaptcha(ctx, task, freecap.HCaptcha, 0, 0)
+package testutil
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	freecap "github.com/freecap-su/Wrappers"
+)
+
+// DefaultSolution is returned for solved tasks when no solution is configured
+const DefaultSolution = "mock-solution"
+
+// MockServer is an HTTP server implementing /CreateTask, /GetTask,
+// /DeleteTask, /GetBalance and /GetSupportedTypes. Tasks are solved after a configurable number
+// of processing polls. Request bodies may be gzip-compressed, as sent with
+// CompressRequests. Its settings may be changed while it is serving.
+type MockServer struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	apiKey         string
+	solution       string
+	solutionByType map[string]string
+	pendingPolls   int
+	taskError      string
+	delay          time.Duration
+	balance        float64
+	supportedTypes []string
+	failures       map[string][]failure
+	calls          map[string]int
+	tasks          map[string]*mockTask
+	nextID         int
+}
+
+// failure is a canned response injected for one request
+type failure struct {
+	status int
+	body   string
+}
+
+// mockTask is a task created on the mock server
+type mockTask struct {
+	captchaType string
+	payload     map[string]interface{}
+	polls       int
+	deleted     bool
+}
+
+// NewMockServer starts a mock server on a local port. Call Close when done.
+func NewMockServer() *MockServer {
+	m := &MockServer{
+		solution:       DefaultSolution,
+		solutionByType: make(map[string]string),
+		failures:       make(map[string][]failure),
+		calls:          make(map[string]int),
+		tasks:          make(map[string]*mockTask),
+		supportedTypes: []string{
+			string(freecap.HCaptcha),
+			string(freecap.CaptchaFox),
+			string(freecap.Geetest),
+			string(freecap.DiscordID),
+			string(freecap.FunCaptcha),
+			string(freecap.Turnstile),
+			string(freecap.RecaptchaV2),
+			string(freecap.RecaptchaV3),
+			string(freecap.AuroNetwork),
+		},
+	}
+	m.Server = httptest.NewServer(m.Handler())
+	return m
+}
+
+// NewClient creates a client pointed at the server, overwriting config.APIURL.
+// A nil config uses the defaults with short check and retry intervals so
+// tests run quickly.
+func (m *MockServer) NewClient(config *freecap.ClientConfig) (*freecap.FreeCapClient, error) {
+	if config == nil {
+		config = freecap.NewClientConfig()
+		config.RetryDelay = 10 * time.Millisecond
+		config.DefaultCheckInterval = 10 * time.Millisecond
+		config.DefaultTaskTimeout = 5 * time.Second
+	}
+	config.APIURL = m.URL
+
+	apiKey := m.apiKeyOrDefault()
+	return freecap.NewFreeCapClient(apiKey, config, &freecap.NullLogger{})
+}
+
+// SetAPIKey makes the server reject requests without this key with 401.
+// By default any key is accepted.
+func (m *MockServer) SetAPIKey(apiKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.apiKey = apiKey
+}
+
+// SetSolution sets the solution returned for solved tasks of any type
+func (m *MockServer) SetSolution(solution string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.solution = solution
+}
+
+// SetSolutionFor sets the solution returned for solved tasks of one type
+func (m *MockServer) SetSolutionFor(captchaType freecap.CaptchaType, solution string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.solutionByType[string(captchaType)] = solution
+}
+
+// SetPendingPolls sets how many GetTask polls report "processing" before a
+// task is solved (default 0, solved on the first poll)
+func (m *MockServer) SetPendingPolls(polls int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingPolls = polls
+}
+
+// SetTaskError makes tasks finish as failed with the given error message
+// instead of solved; an empty message restores solving
+func (m *MockServer) SetTaskError(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.taskError = message
+}
+
+// SetDelay delays every response by d
+func (m *MockServer) SetDelay(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delay = d
+}
+
+// SetBalance sets the balance reported by /GetBalance
+func (m *MockServer) SetBalance(balance float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balance = balance
+}
+
+// SetSupportedTypes sets the list reported by /GetSupportedTypes
+func (m *MockServer) SetSupportedTypes(types []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.supportedTypes = append([]string(nil), types...)
+}
+
+// InjectFailure queues a canned response for the next request to endpoint
+// (e.g. "/CreateTask"). Injected failures are used in order, one per request.
+func (m *MockServer) InjectFailure(endpoint string, status int, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	endpoint = "/" + strings.TrimLeft(endpoint, "/")
+	m.failures[endpoint] = append(m.failures[endpoint], failure{status: status, body: body})
+}
+
+// Calls returns how many requests endpoint has received
+func (m *MockServer) Calls(endpoint string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls["/"+strings.TrimLeft(endpoint, "/")]
+}
+
+// TaskPayload returns the payload a task was created with
+func (m *MockServer) TaskPayload(taskID string) (map[string]interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task, ok := m.tasks[taskID]
+	if !ok {
+		return nil, false
+	}
+	return task.payload, true
+}
+
+// Handler returns the mock API as an http.Handler, for use without a socket
+func (m *MockServer) Handler() http.Handler {
+	return http.HandlerFunc(m.serveHTTP)
+}
+
+func (m *MockServer) apiKeyOrDefault() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.apiKey != "" {
+		return m.apiKey
+	}
+	return "mock-api-key"
+}
+
+func (m *MockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.calls[r.URL.Path]++
+	delay := m.delay
+	var injected *failure
+	if queued := m.failures[r.URL.Path]; len(queued) > 0 {
+		injected = &queued[0]
+		m.failures[r.URL.Path] = queued[1:]
+	}
+	apiKey := m.apiKey
+	m.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if injected != nil {
+		w.WriteHeader(injected.status)
+		_, _ = w.Write([]byte(injected.body))
+		return
+	}
+
+	if apiKey != "" && r.Header.Get("FreeCap-Key") != apiKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"status": false, "error": "Invalid API key"})
+		return
+	}
+
+	reader, err := requestBody(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"status": false, "error": err.Error()})
+		return
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(reader).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"status": false, "error": "Invalid JSON body"})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/CreateTask":
+		m.createTask(w, body)
+	case "/GetTask":
+		m.getTask(w, body)
+	case "/DeleteTask":
+		m.deleteTask(w, body)
+	case "/GetBalance":
+		m.mu.Lock()
+		balance := m.balance
+		m.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": true, "balance": balance})
+	case "/GetSupportedTypes":
+		m.mu.Lock()
+		types := append([]string(nil), m.supportedTypes...)
+		m.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": true, "types": types})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"status": false, "error": "Unknown endpoint"})
+	}
+}
+
+// requestBody returns the request body, gunzipped when the client sent it
+// with Content-Encoding: gzip. Other encodings are rejected.
+func requestBody(r *http.Request) (io.Reader, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return r.Body, nil
+	case "gzip":
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid gzip body: %v", err)
+		}
+		return zr, nil
+	default:
+		return nil, fmt.Errorf("Unsupported Content-Encoding %q", encoding)
+	}
+}
+
+func (m *MockServer) createTask(w http.ResponseWriter, body map[string]interface{}) {
+	captchaType, _ := body["captchaType"].(string)
+	if captchaType == "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": false, "error": "captchaType is required"})
+		return
+	}
+	payload, _ := body["payload"].(map[string]interface{})
+
+	m.mu.Lock()
+	m.nextID++
+	taskID := fmt.Sprintf("mock-task-%d", m.nextID)
+	m.tasks[taskID] = &mockTask{captchaType: captchaType, payload: payload}
+	m.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": true, "taskId": taskID})
+}
+
+func (m *MockServer) getTask(w http.ResponseWriter, body map[string]interface{}) {
+	taskID, _ := body["taskId"].(string)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, ok := m.tasks[taskID]
+	if !ok || task.deleted {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "error", "error": "Task not found"})
+		return
+	}
+
+	task.polls++
+	if task.polls <= m.pendingPolls {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "processing", "taskId": taskID})
+		return
+	}
+
+	if m.taskError != "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "failed", "taskId": taskID, "error": m.taskError})
+		return
+	}
+
+	solution := m.solution
+	if typed, ok := m.solutionByType[task.captchaType]; ok {
+		solution = typed
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "solved", "taskId": taskID, "solution": solution})
+}
+
+func (m *MockServer) deleteTask(w http.ResponseWriter, body map[string]interface{}) {
+	taskID, _ := body["taskId"].(string)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, ok := m.tasks[taskID]
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": false, "error": "Task not found"})
+		return
+	}
+	task.deleted = true
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": true})
+}
+
+func writeJSON(w http.ResponseWriter, status int, data map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
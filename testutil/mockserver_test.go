@@ -0,0 +1,110 @@
+package testutil_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	freecap "github.com/freecap-su/Wrappers"
+	"github.com/freecap-su/Wrappers/testutil"
+)
+
+func ExampleMockServer() {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.SetSolution("P1_mock-token")
+	server.SetPendingPolls(2)
+
+	client, err := server.NewClient(nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer client.Close()
+
+	task := &freecap.CaptchaTask{Sitekey: "sitekey", Siteurl: "example.com"}
+	solution, err := client.SolveCaptcha(context.Background(), task, freecap.HCaptcha, 0, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(solution)
+	fmt.Println(server.Calls("/CreateTask"), server.Calls("/GetTask"))
+	// Output:
+	// P1_mock-token
+	// 1 3
+}
+
+// encodingRecorder records the Content-Encoding of every request it sends
+type encodingRecorder struct {
+	mu        sync.Mutex
+	encodings []string
+}
+
+func (r *encodingRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.encodings = append(r.encodings, req.Header.Get("Content-Encoding"))
+	r.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestMockServerReadsGzipBodies(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	recorder := &encodingRecorder{}
+	config := freecap.NewClientConfig()
+	config.DefaultCheckInterval = 5 * time.Millisecond
+	config.CompressRequests = true
+	config.CompressThreshold = 1
+	config.Transport = recorder
+	client, err := server.NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	task := &freecap.CaptchaTask{Sitekey: "sitekey", Siteurl: "example.com", Action: "login"}
+	taskID, err := client.CreateTask(context.Background(), task, freecap.Turnstile)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	recorder.mu.Lock()
+	encodings := append([]string(nil), recorder.encodings...)
+	recorder.mu.Unlock()
+	if len(encodings) != 1 || encodings[0] != "gzip" {
+		t.Errorf("request encodings = %q, want one gzip request", encodings)
+	}
+
+	payload, ok := server.TaskPayload(taskID)
+	if !ok {
+		t.Fatalf("task %s not recorded", taskID)
+	}
+	if payload["websiteKey"] != "sitekey" || payload["action"] != "login" {
+		t.Errorf("payload = %v, want the decompressed task fields", payload)
+	}
+}
+
+func TestMockServerRejectsUnknownEncoding(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL+"/GetBalance", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Encoding", "br")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400 for an unknown Content-Encoding", resp.StatusCode)
+	}
+}
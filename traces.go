@@ -0,0 +1,88 @@
+package freecap
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceBodyLimit caps the request and response bodies kept in a trace
+const traceBodyLimit = 2048
+
+// RequestTrace records one HTTP attempt for post-mortem debugging. Bodies are
+// truncated to 2 KiB with API keys and proxy credentials masked.
+type RequestTrace struct {
+	Time       time.Time
+	Method     string
+	URL        string
+	Attempt    int
+	StatusCode int
+	Duration   time.Duration
+	Request    string
+	Response   string
+	Err        string
+}
+
+// traceBuffer keeps the most recent request traces in a ring
+type traceBuffer struct {
+	mu      sync.Mutex
+	entries []RequestTrace
+	next    int
+	full    bool
+}
+
+func newTraceBuffer(size int) *traceBuffer {
+	return &traceBuffer{entries: make([]RequestTrace, size)}
+}
+
+func (b *traceBuffer) add(trace RequestTrace) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = trace
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// snapshot returns the buffered traces, oldest first
+func (b *traceBuffer) snapshot() []RequestTrace {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]RequestTrace(nil), b.entries[:b.next]...)
+	}
+	traces := make([]RequestTrace, 0, len(b.entries))
+	traces = append(traces, b.entries[b.next:]...)
+	return append(traces, b.entries[:b.next]...)
+}
+
+// RecentTraces returns up to TraceBufferSize of the latest request attempts,
+// oldest first. It returns nil when tracing is disabled.
+func (c *FreeCapClient) RecentTraces() []RequestTrace {
+	if c.traces == nil {
+		return nil
+	}
+	return c.traces.snapshot()
+}
+
+// traceBody renders a payload for a trace, redacted and truncated
+func traceBody(payload map[string]interface{}, raw []byte) string {
+	if payload != nil {
+		if encoded, err := json.Marshal(redactSensitive(payload)); err == nil {
+			raw = encoded
+		}
+	}
+	return truncate(string(raw), traceBodyLimit)
+}
+
+// truncate shortens s to at most limit bytes, marking the cut
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return strings.ToValidUTF8(s[:limit], "") + "...(truncated)"
+}
@@ -0,0 +1,52 @@
+package freecap
+
+import "context"
+
+// Tracer starts spans around client operations. It lets tracing systems be
+// plugged in without the core client depending on them; see the freecapotel
+// package for an OpenTelemetry implementation.
+type Tracer interface {
+	// Start begins a span named name as a child of any span in ctx and
+	// returns a context carrying the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation being traced
+type Span interface {
+	// SetAttribute records a key/value attribute on the span
+	SetAttribute(key string, value interface{})
+	// End finishes the span, marking it failed when err is non-nil
+	End(err error)
+}
+
+// Span names used by the client
+const (
+	SpanSolveCaptcha  = "freecap.SolveCaptcha"
+	SpanCreateTask    = "freecap.CreateTask"
+	SpanGetTaskResult = "freecap.GetTaskResult"
+	SpanRequest       = "freecap.request"
+)
+
+// Span attribute keys used by the client
+const (
+	AttrCaptchaType    = "freecap.captcha_type"
+	AttrTaskID         = "freecap.task_id"
+	AttrAttempt        = "freecap.attempt"
+	AttrHTTPMethod     = "http.request.method"
+	AttrURL            = "url.full"
+	AttrHTTPStatusCode = "http.response.status_code"
+)
+
+// noopSpan is used when no Tracer is configured
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) End(err error)                              {}
+
+// startSpan starts a span with the configured Tracer, or a no-op span
+func (c *FreeCapClient) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c.config.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.config.Tracer.Start(ctx, name)
+}
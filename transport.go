@@ -0,0 +1,49 @@
+package freecap
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// Connection pool defaults. Every request goes to the same FreeCap host, so
+// the per-host idle limit is raised well above net/http's default of 2 to
+// avoid reconnecting under concurrent solving.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 32
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// newTransport builds the transport used by the internally created HTTP
+// client from the connection pool and TLS settings in config
+func newTransport(config *ClientConfig, logger Logger) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.MaxIdleConns = defaultMaxIdleConns
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+		if tlsConfig.MinVersion == 0 {
+			tlsConfig.MinVersion = tls.VersionTLS12
+		}
+	}
+	if tlsConfig.InsecureSkipVerify {
+		logger.Warning("TLS certificate verification is disabled")
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport
+}
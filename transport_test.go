@@ -0,0 +1,132 @@
+package freecap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewTransportPoolSettings(t *testing.T) {
+	config := NewClientConfig()
+	transport := newTransport(config, &NullLogger{})
+	if transport.MaxIdleConns != defaultMaxIdleConns || transport.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost ||
+		transport.IdleConnTimeout != defaultIdleConnTimeout {
+		t.Errorf("default pool = %d/%d/%v, want %d/%d/%v",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout,
+			defaultMaxIdleConns, defaultMaxIdleConnsPerHost, defaultIdleConnTimeout)
+	}
+
+	config.MaxIdleConns = 10
+	config.MaxIdleConnsPerHost = 4
+	config.IdleConnTimeout = time.Second
+	transport = newTransport(config, &NullLogger{})
+	if transport.MaxIdleConns != 10 || transport.MaxIdleConnsPerHost != 4 || transport.IdleConnTimeout != time.Second {
+		t.Errorf("configured pool = %d/%d/%v, want 10/4/1s",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+// BenchmarkConcurrentConnections compares how many connections concurrent
+// pollers open with net/http's per-host idle limit of 2 and with the
+// client's default. Compare the conns/op metric of the two runs.
+func BenchmarkConcurrentConnections(b *testing.B) {
+	for _, bench := range []struct {
+		name    string
+		perHost int
+	}{
+		{"stdlib-default", 2},
+		{"client-default", 0},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			var opened atomic.Int64
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeTestJSON(w, 200, map[string]interface{}{"status": true, "balance": 1.0})
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					opened.Add(1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			config := NewClientConfig()
+			config.APIURL = server.URL
+			config.MaxIdleConnsPerHost = bench.perHost
+			client, err := NewFreeCapClient("test-api-key", config, &NullLogger{})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer client.Close()
+
+			const workers = 16
+			b.ResetTimer()
+			var wg sync.WaitGroup
+			var next atomic.Int64
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for next.Add(1) <= int64(b.N) {
+						if _, err := client.GetBalance(context.Background()); err != nil {
+							b.Error(err)
+							return
+						}
+						// Like polling, leave the connection idle between requests
+						time.Sleep(100 * time.Microsecond)
+					}
+				}()
+			}
+			wg.Wait()
+			b.ReportMetric(float64(opened.Load())/float64(b.N), "conns/op")
+		})
+	}
+}
+
+func TestTLSConfigVerifiesAgainstCAPool(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, 200, map[string]interface{}{"status": true, "balance": 7.0})
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	trusted := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.TLSConfig = &tls.Config{RootCAs: pool}
+	})
+	balance, err := trusted.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("GetBalance with the server's CA: %v", err)
+	}
+	if balance != 7.0 {
+		t.Errorf("balance = %v, want 7", balance)
+	}
+
+	untrusted := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxRetries = 0
+	})
+	_, err = untrusted.GetBalance(context.Background())
+	var unknownAuthority x509.UnknownAuthorityError
+	if !errors.As(err, &unknownAuthority) {
+		t.Errorf("GetBalance without the CA = %v, want an unknown authority error", err)
+	}
+}
+
+func TestTLSConfigKeepsMinimumVersion(t *testing.T) {
+	transport := newTransport(&ClientConfig{TLSConfig: &tls.Config{ServerName: "api.example.com"}}, &NullLogger{})
+	if transport.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", transport.TLSClientConfig.MinVersion)
+	}
+	if transport.TLSClientConfig.ServerName != "api.example.com" {
+		t.Errorf("ServerName = %q, want the configured one", transport.TLSClientConfig.ServerName)
+	}
+}